@@ -0,0 +1,232 @@
+package initq
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+/* ------------------------------------------------------------------------ */
+
+// TaskTiming is the (critical path) analysis of a single task. All times
+// other than Duration are offsets from the start of processing under full
+// parallelism.
+type TaskTiming struct {
+	// Name is the task label.
+	Name string
+
+	// Duration is the time of the task function call that Satisfied the
+	// task.
+	Duration time.Duration
+
+	// Wasted is the time spent in calls that did not Satisfy the task (such
+	// as TryAgain).
+	Wasted time.Duration
+
+	// EarliestStart and EarliestFinish are the soonest the task could start
+	// and finish (once all of its dependencies have finished).
+	EarliestStart  time.Duration
+	EarliestFinish time.Duration
+
+	// LatestStart is the latest the task could start without delaying the
+	// (theoretical) end of processing.
+	LatestStart time.Duration
+
+	// Slack is the amount of time the task could be delayed without delaying
+	// processing (LatestStart - EarliestStart).
+	Slack time.Duration
+
+	// Critical is true when the task is on the critical path.
+	Critical bool
+}
+
+/* ------------------------------------------------------------------------ */
+
+// Analysis is the critical path analysis of a processed Q.
+//
+// Only explicit dependencies are known. Tasks that depend on each other by
+// 'sense' are considered independent - so the analysis is of the Q as it
+// could be (if dependencies were explicit) under full parallelism.
+type Analysis struct {
+	// Tasks are the timings of each Satisfied task. They are ordered by
+	// earliest start (and then name).
+	Tasks []TaskTiming
+
+	// CriticalPath is the longest chain of dependent tasks (first to last).
+	CriticalPath []string
+
+	// Actual is the time from the first task call to the last task return.
+	Actual time.Duration
+
+	// Minimum is the theoretical minimum processing time under full
+	// parallelism (the length of the critical path).
+	Minimum time.Duration
+}
+
+/* ======================================================================== */
+
+// Analyze computes the critical path (and each task's slack) from the trace
+// of the last Process call and the explicit dependencies of the Q. Tasks that
+// were not Satisfied in the last call are not included.
+func (rq *InitQ) Analyze() (a *Analysis) {
+
+	rq.mu.Lock()
+	deps := make(map[string][]string)
+	for _, rqi := range rq.q {
+		deps[rqi.name] = slices.Clone(rqi.deps)
+	}
+	rq.mu.Unlock()
+
+	return analyze(rq.Trace(), deps)
+}
+
+/* ======================================================================== */
+
+// analyze is the implementation of Analyze (on a trace and dependency map).
+func analyze(trace []Attempt, deps map[string][]string) (a *Analysis) {
+
+	a = new(Analysis)
+
+	if len(trace) == 0 {
+		return
+	}
+
+	// Durations (and the actual elapsed time) from the trace.
+	timings := make(map[string]*TaskTiming)
+	first, last := trace[0].Start, trace[0].End
+	wasted := make(map[string]time.Duration)
+
+	for _, at := range trace {
+		if at.Start.Before(first) {
+			first = at.Start
+		}
+		if at.End.After(last) {
+			last = at.End
+		}
+
+		if at.Result == Satisfied {
+			timings[at.Task] = &TaskTiming{Name: at.Task, Duration: at.End.Sub(at.Start)}
+		} else {
+			wasted[at.Task] += at.End.Sub(at.Start)
+		}
+	}
+
+	a.Actual = last.Sub(first)
+
+	// Forward: earliest finish is the latest dependency finish plus the
+	// task's own duration. The recursion is memoized with the done map. The
+	// order tasks finish (after their dependencies) is a topological order.
+	done := make(map[string]bool)
+	order := make([]*TaskTiming, 0, len(timings))
+	var forward func(name string) time.Duration
+	forward = func(name string) time.Duration {
+
+		tt := timings[name]
+		if done[name] {
+			return tt.EarliestFinish
+		}
+		done[name] = true
+
+		for _, d := range deps[name] {
+			if _, ok := timings[d]; ok {
+				tt.EarliestStart = max(tt.EarliestStart, forward(d))
+			}
+		}
+
+		tt.EarliestFinish = tt.EarliestStart + tt.Duration
+		order = append(order, tt)
+		return tt.EarliestFinish
+	}
+
+	var end string
+	for name, tt := range timings {
+		tt.Wasted = wasted[name]
+		if ef := forward(name); ef > a.Minimum || (ef == a.Minimum && (end == "" || name < end)) {
+			a.Minimum = ef
+			end = name
+		}
+	}
+
+	// Backward: latest finish is the earliest latest-start of the dependants
+	// (or the minimum for those with no dependants).
+	latestFinish := make(map[string]time.Duration)
+	for name := range timings {
+		latestFinish[name] = a.Minimum
+	}
+
+	// Process in reverse topological order so dependants are complete
+	// before their dependencies. (Earliest finish is not enough - a zero
+	// duration dependant ties with its dependency.)
+	slices.Reverse(order)
+
+	for _, tt := range order {
+		tt.LatestStart = latestFinish[tt.Name] - tt.Duration
+		tt.Slack = tt.LatestStart - tt.EarliestStart
+
+		for _, d := range deps[tt.Name] {
+			if _, ok := timings[d]; ok {
+				latestFinish[d] = min(latestFinish[d], tt.LatestStart)
+			}
+		}
+	}
+
+	// Walk back from the end of the critical path - through the dependency
+	// that finished last.
+	for name := end; name != ""; {
+
+		a.CriticalPath = append([]string{name}, a.CriticalPath...)
+		timings[name].Critical = true
+
+		next := ""
+		for _, d := range deps[name] {
+			dt, ok := timings[d]
+			if ok && dt.EarliestFinish == timings[name].EarliestStart && (next == "" || d < next) {
+				next = d
+			}
+		}
+		name = next
+	}
+
+	for _, tt := range timings {
+		a.Tasks = append(a.Tasks, *tt)
+	}
+	slices.SortFunc(a.Tasks, func(x, y TaskTiming) int {
+		if c := cmp.Compare(x.EarliestStart, y.EarliestStart); c != 0 {
+			return c
+		}
+		return strings.Compare(x.Name, y.Name)
+	})
+
+	return
+}
+
+/* ======================================================================== */
+
+// WriteTable writes the analysis as a (text) table.
+func (a *Analysis) WriteTable(w io.Writer) (err error) {
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "TASK\tDURATION\tWASTED\tSTART\tFINISH\tSLACK\tCRITICAL")
+	for _, tt := range a.Tasks {
+		crit := ""
+		if tt.Critical {
+			crit = "*"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			tt.Name, tt.Duration, tt.Wasted, tt.EarliestStart, tt.EarliestFinish, tt.Slack, crit)
+	}
+
+	if err = tw.Flush(); err != nil {
+		return
+	}
+
+	_, err = fmt.Fprintf(w, "\nCritical path: %s\nActual: %s  Minimum: %s\n",
+		strings.Join(a.CriticalPath, " -> "), a.Actual, a.Minimum)
+
+	return
+}
@@ -0,0 +1,137 @@
+package initq
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+/* ======================================================================== */
+
+func TestAnalysis(t *testing.T) {
+
+	var a *Analysis
+
+	// at builds an attempt (times are offsets in ms from a fixed base).
+	base := time.Now()
+	at := func(name string, res ReqResult, from, to int) Attempt {
+		return Attempt{
+			Task:   name,
+			Result: res,
+			Start:  base.Add(time.Duration(from) * time.Millisecond),
+			End:    base.Add(time.Duration(to) * time.Millisecond),
+		}
+	}
+
+	// ----------
+	// A sequential trace of a Q with two branches.
+	//
+	//   cmdline(10) -> config(20) -> db(50) -> server(10)
+	//                             -> cache(5)
+	//   metrics(15)
+
+	deps := map[string][]string{
+		"cmdline": nil,
+		"config":  {"cmdline"},
+		"db":      {"config"},
+		"cache":   {"config"},
+		"server":  {"db", "cache"},
+		"metrics": nil,
+	}
+
+	trace := []Attempt{
+		at("server", TryAgain, 0, 1),
+		at("cmdline", Satisfied, 1, 11),
+		at("config", Satisfied, 11, 31),
+		at("db", Satisfied, 31, 81),
+		at("cache", Satisfied, 81, 86),
+		at("metrics", Satisfied, 86, 101),
+		at("server", Satisfied, 101, 111),
+	}
+
+	a = analyze(trace, deps)
+
+	if !slices.Equal(a.CriticalPath, []string{"cmdline", "config", "db", "server"}) {
+		t.Errorf("Unexpected critical path %v", a.CriticalPath)
+	}
+
+	if a.Minimum != 90*time.Millisecond {
+		t.Errorf("Expected a minimum of 90ms; got %s", a.Minimum)
+	}
+
+	if a.Actual != 111*time.Millisecond {
+		t.Errorf("Expected an actual of 111ms; got %s", a.Actual)
+	}
+
+	for _, tt := range a.Tasks {
+		switch tt.Name {
+		case "cache":
+			if tt.Slack != 45*time.Millisecond || tt.Critical {
+				t.Errorf("Expected cache slack of 45ms; got %s", tt.Slack)
+			}
+		case "metrics":
+			if tt.Slack != 75*time.Millisecond {
+				t.Errorf("Expected metrics slack of 75ms; got %s", tt.Slack)
+			}
+		case "server":
+			if tt.Slack != 0 || !tt.Critical || tt.Wasted != time.Millisecond || tt.EarliestStart != 80*time.Millisecond {
+				t.Errorf("Unexpected server timing %+v", tt)
+			}
+		}
+	}
+
+	// ----------
+	// The table.
+
+	var buf bytes.Buffer
+	if err := a.WriteTable(&buf); err != nil {
+		t.Errorf("Unexpected WriteTable error - %s", err.Error())
+	}
+
+	if !strings.Contains(buf.String(), "cmdline -> config -> db -> server") {
+		t.Errorf("Missing the critical path in the table:\n%s", buf.String())
+	}
+
+	// ----------
+	// A zero duration dependant (that sorts after its dependency) is still
+	// processed before it.
+	//
+	//   b(10) -> z(0) -> y(5)
+
+	a = analyze([]Attempt{
+		at("b", Satisfied, 0, 10),
+		at("z", Satisfied, 10, 10),
+		at("y", Satisfied, 10, 15),
+	}, map[string][]string{"z": {"b"}, "y": {"z"}})
+
+	for _, tt := range a.Tasks {
+		if tt.Slack != 0 || !tt.Critical {
+			t.Errorf("Expected %s to be critical (with no slack); got %+v", tt.Name, tt)
+		}
+	}
+
+	// ----------
+	// A real Q (timings are not deterministic - just the shape).
+
+	rq := NewInitQ()
+
+	rq.Add("one", func() ReqResult { return Satisfied })
+	rq.Add("two", func() ReqResult { return Satisfied }, "one")
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	a = rq.Analyze()
+	if len(a.Tasks) != 2 || !slices.Equal(a.CriticalPath, []string{"one", "two"}) {
+		t.Errorf("Unexpected analysis %+v", a)
+	}
+
+	// An empty trace.
+	if a = NewInitQ().Analyze(); len(a.Tasks) != 0 {
+		t.Errorf("Expected an empty analysis")
+	}
+
+}
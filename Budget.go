@@ -0,0 +1,49 @@
+package initq
+
+/* ======================================================================== */
+
+// SetMaxPasses sets the maximum number of passes of the Q in a process run.
+// A value of zero (the default) is one more pass than there are tasks - the
+// worst case for a Q of tasks that each only need their dependencies. Tasks
+// that poll (and legitimately return TryAgain many times) need more.
+//
+// It must not be called while the Q is processing.
+func (rq *InitQ) SetMaxPasses(max int) {
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	rq.maxPasses = max
+}
+
+/* ======================================================================== */
+
+// SetStallPasses enables stall detection. Processing is abandoned once this
+// many passes in a row have Satisfied nothing new (and added no tasks). A
+// stalled Q is reported distinctly (see ErrQStalled) from one that used up
+// its pass budget. A value of zero (the default) disables stall detection.
+//
+// The ScheduleEvents scheduler always stalls after a single pass without
+// progress.
+//
+// It must not be called while the Q is processing.
+func (rq *InitQ) SetStallPasses(n int) {
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	rq.stallPasses = n
+}
+
+/* ======================================================================== */
+
+// stallLimit is the number of passes (in a row) without progress that
+// stall the Q. Zero is never.
+func (rq *InitQ) stallLimit() int {
+
+	if rq.scheduler == ScheduleEvents {
+		return 1
+	}
+
+	return rq.stallPasses
+}
@@ -0,0 +1,114 @@
+package initq
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+/* ======================================================================== */
+
+func TestBudget(t *testing.T) {
+
+	var sim *Simulation
+	var sr SimResult
+
+	// ----------
+	// A polling task that needs more passes than the default budget.
+
+	sim = NewSimulation()
+
+	sim.Add("sidecar", SatisfiedOnAttempt(10))
+	sim.Add("client", Always(Satisfied), "sidecar")
+
+	sr = sim.Run()
+
+	if _, ok := sr.Err.(*QUnresolvable); !ok || sr.Passes != 3 {
+		t.Errorf("Expected the default budget (3) to be exhausted; got %d / %v", sr.Passes, sr.Err)
+	}
+
+	sim = NewSimulation()
+	sim.SetMaxPasses(20)
+
+	sim.Add("sidecar", SatisfiedOnAttempt(10))
+	sim.Add("client", Always(Satisfied), "sidecar")
+
+	sr = sim.Run()
+
+	if sr.Err != nil || sr.Passes != 10 || sr.Bound != 20 {
+		t.Errorf("Expected 10 of 20 passes; got %d of %d (%v)", sr.Passes, sr.Bound, sr.Err)
+	}
+
+	// ----------
+	// A budget that is exhausted is not a stall.
+
+	var qur *QUnresolvable
+
+	sim = NewSimulation()
+	sim.SetMaxPasses(5)
+
+	sim.Add("never", Always(TryAgain))
+
+	sr = sim.Run()
+
+	if !errors.As(sr.Err, &qur) || qur.Stalled() || errors.Is(sr.Err, ErrQStalled) || sr.Passes != 5 {
+		t.Errorf("Expected an exhausted budget; got %d passes / %v", sr.Passes, sr.Err)
+	}
+
+	// ----------
+	// A cycle stalls (without using up a large budget).
+
+	sim = NewSimulation()
+	sim.SetMaxPasses(1000)
+	sim.SetStallPasses(2)
+
+	sim.Add("one", Always(Satisfied))
+	sim.Add("black", Always(Satisfied), "white")
+	sim.Add("white", Always(Satisfied), "black")
+
+	sr = sim.Run()
+
+	if !errors.As(sr.Err, &qur) || !qur.Stalled() || !errors.Is(sr.Err, ErrQStalled) {
+		t.Fatalf("Expected a stalled Q; got %v", sr.Err)
+	}
+
+	// The first pass satisfied one - then two passes without progress.
+	if sr.Passes != 3 {
+		t.Errorf("Expected 3 passes; got %d", sr.Passes)
+	}
+
+	if !strings.Contains(sr.Err.Error(), "stalled") || len(qur.RootCauses()) != 2 {
+		t.Errorf("Unexpected stall error %s (roots %v)", sr.Err.Error(), qur.RootCauses())
+	}
+
+	// ----------
+	// Stall detection tolerates polling - as long as the stall limit is more
+	// than the polling passes.
+
+	sim = NewSimulation()
+	sim.SetMaxPasses(20)
+	sim.SetStallPasses(5)
+
+	sim.Add("sidecar", SatisfiedOnAttempt(4))
+
+	if sr = sim.Run(); sr.Err != nil {
+		t.Errorf("Simulated Q did not finish - %s", sr.Err.Error())
+	}
+
+	// ----------
+	// The testable behaviour returns the sentinel.
+
+	BehaveUnresolvIsErr = true
+
+	rq := NewInitQ()
+	rq.SetStallPasses(1)
+
+	rq.Add("never", func() ReqResult { return TryAgain })
+
+	if err := rq.Process(); err != ErrQStalled {
+		t.Errorf("Expected ErrQStalled; got %v", err)
+	}
+
+	BehaveUnresolvIsErr = false
+
+}
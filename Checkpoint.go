@@ -0,0 +1,194 @@
+package initq
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+)
+
+/*
+	Checkpoints:
+
+	- With a CheckpointStore (see Checkpoint), the Satisfied tasks are saved
+	  each time a task is Satisfied (or compensated).
+	- On the first process run, the tasks in the saved checkpoint are
+	  marked Satisfied without being called. A long one-time setup that
+	  failed part way resumes where it stopped.
+	- Tasks that must always run (AlwaysRun) are neither saved nor restored.
+	- The checkpoint is only loaded once. After that, the state of the Q
+	  (Reset, ResetTask, a Supervisor restart) is what is saved.
+	- Each save is the whole set of currently Satisfied (non-AlwaysRun)
+	  tasks, so the store shrinks too. A compensated task is dropped at
+	  once, and a task cleared by Reset or ResetTask is dropped by the
+	  next save. Delete the file to start over.
+*/
+
+/* ------------------------------------------------------------------------ */
+
+// CheckpointStore persists the Satisfied tasks of a Q between runs of an
+// application. A store that has nothing saved returns no tasks (and no
+// error).
+type CheckpointStore interface {
+	LoadCheckpoint() ([]string, error)
+	SaveCheckpoint(satisfied []string) error
+}
+
+/* ------------------------------------------------------------------------ */
+
+// fileCheckpointStore is the (JSON) file based CheckpointStore.
+type fileCheckpointStore struct {
+	path string
+}
+
+/* ======================================================================== */
+
+// FileCheckpointStore returns a CheckpointStore that saves to a JSON file.
+// A missing file is an empty checkpoint.
+func FileCheckpointStore(path string) CheckpointStore {
+	return &fileCheckpointStore{path: path}
+}
+
+/* ======================================================================== */
+
+// LoadCheckpoint reads the Satisfied tasks from the file.
+func (fcs *fileCheckpointStore) LoadCheckpoint() (satisfied []string, err error) {
+
+	data, err := os.ReadFile(fcs.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(data, &satisfied)
+	return
+}
+
+/* ======================================================================== */
+
+// SaveCheckpoint writes the Satisfied tasks to the file. (As SaveOrder, it
+// is written to a temporary file and renamed.)
+func (fcs *fileCheckpointStore) SaveCheckpoint(satisfied []string) (err error) {
+
+	data, err := json.Marshal(satisfied)
+	if err != nil {
+		return
+	}
+
+	return replaceFile(fcs.path, data)
+}
+
+/* ======================================================================== */
+
+// Checkpoint enables checkpoints of the Q. See the notes (above).
+//
+// Store errors do not cause processing to fail. The last error is available
+// from CheckpointErr. A failed load runs every task.
+func (rq *InitQ) Checkpoint(store CheckpointStore) {
+
+	rq.mu.Lock()
+	rq.checkpoints = store
+	rq.mu.Unlock()
+}
+
+/* ======================================================================== */
+
+// AlwaysRun opts the named task out of checkpoints. It is run on every
+// start - even if it was Satisfied in a previous run.
+//
+// An error (ErrQNoTask) is returned if the name does not match a task.
+func (rq *InitQ) AlwaysRun(name string) (err error) {
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	rqi := rq.item(name)
+	if rqi == nil {
+		return fmt.Errorf("%w: %s", ErrQNoTask, name)
+	}
+
+	rqi.always = true
+
+	return
+}
+
+/* ======================================================================== */
+
+// Restored returns the tasks that were marked Satisfied (without being
+// called) from the checkpoint.
+func (rq *InitQ) Restored() []string {
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	return slices.Clone(rq.restored)
+}
+
+/* ======================================================================== */
+
+// CheckpointErr returns the last error from the CheckpointStore (or nil).
+func (rq *InitQ) CheckpointErr() error {
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	return rq.checkpointErr
+}
+
+/* ======================================================================== */
+
+// restoreCheckpoint marks the tasks of the saved checkpoint Satisfied. This
+// is only done once (for the first process run). It must be called with the
+// lock held.
+func (rq *InitQ) restoreCheckpoint() {
+
+	if rq.checkpoints == nil || rq.checkpointed {
+		return
+	}
+	rq.checkpointed = true
+
+	satisfied, err := rq.checkpoints.LoadCheckpoint()
+	if err != nil {
+		rq.checkpointErr = err
+		return
+	}
+
+	// The saved order is the order of completion.
+	for _, name := range satisfied {
+
+		rqi := rq.item(name)
+		if rqi == nil || rqi.always || rqi.state == Satisfied {
+			continue
+		}
+
+		rqi.state = Satisfied
+		rq.seq++
+		rqi.seq = rq.seq
+
+		rq.restored = append(rq.restored, name)
+	}
+}
+
+/* ======================================================================== */
+
+// saveCheckpoint saves the Satisfied tasks (that are not AlwaysRun). It must
+// be called with the lock held.
+func (rq *InitQ) saveCheckpoint() {
+
+	if rq.checkpoints == nil {
+		return
+	}
+
+	satisfied := slices.DeleteFunc(rq.completed(), func(name string) bool { return rq.item(name).always })
+	if satisfied == nil {
+		satisfied = []string{}
+	}
+
+	if err := rq.checkpoints.SaveCheckpoint(satisfied); err != nil {
+		rq.checkpointErr = err
+	}
+}
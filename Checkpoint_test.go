@@ -0,0 +1,134 @@
+package initq
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+/* ======================================================================== */
+
+// failCheckpoints is a CheckpointStore that always fails.
+type failCheckpoints struct{}
+
+func (failCheckpoints) LoadCheckpoint() ([]string, error) { return nil, errors.New("load failed") }
+func (failCheckpoints) SaveCheckpoint([]string) error     { return errors.New("save failed") }
+
+/* ======================================================================== */
+
+func TestCheckpoint(t *testing.T) {
+
+	store := FileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+
+	var ran []string
+
+	// build creates a multi-step setup. The seed step returns the result.
+	build := func(store CheckpointStore, seed ReqResult) *InitQ {
+
+		rq := NewInitQ()
+
+		step := func(name string, r ReqResult) QFunc {
+			return func() ReqResult {
+				ran = append(ran, name)
+				return r
+			}
+		}
+
+		rq.Add("buckets", step("buckets", Satisfied))
+		rq.Add("migrate", step("migrate", Satisfied), "buckets")
+		rq.Add("announce", step("announce", Satisfied))
+		rq.Add("seed", step("seed", seed), "migrate")
+
+		rq.Checkpoint(store)
+
+		if err := rq.AlwaysRun("announce"); err != nil {
+			t.Errorf("Unexpected error %s", err.Error())
+		}
+
+		ran = nil
+
+		return rq
+	}
+
+	// ----------
+	// The first run fails at the last step.
+
+	rq := build(store, Stop)
+
+	if err := rq.Process(); err != ErrQStopped {
+		t.Errorf("Expected ErrQStopped; got %v", err)
+	}
+
+	saved, err := store.LoadCheckpoint()
+	if err != nil || !slices.Equal(saved, []string{"buckets", "migrate"}) {
+		t.Errorf("Unexpected checkpoint %v (%v)", saved, err)
+	}
+
+	// ----------
+	// The re-run resumes (but always runs announce).
+
+	rq = build(store, Satisfied)
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if !slices.Equal(ran, []string{"announce", "seed"}) {
+		t.Errorf("Expected only announce and seed to run; got %v", ran)
+	}
+
+	if restored := rq.Restored(); !slices.Equal(restored, []string{"buckets", "migrate"}) {
+		t.Errorf("Unexpected restored tasks %v", restored)
+	}
+
+	if saved, _ = store.LoadCheckpoint(); !slices.Equal(saved, []string{"buckets", "migrate", "seed"}) {
+		t.Errorf("Unexpected checkpoint %v", saved)
+	}
+
+	// ----------
+	// The checkpoint is only restored once.
+
+	rq.Reset()
+	ran = nil
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if len(ran) != 4 {
+		t.Errorf("Expected every task to run after Reset; got %v", ran)
+	}
+
+	// ----------
+	// Compensated tasks are removed from the checkpoint.
+
+	store = FileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+
+	rq = build(store, Stop)
+	rq.SetCompensation("migrate", func() error { return nil })
+
+	rq.Process()
+
+	if saved, _ = store.LoadCheckpoint(); !slices.Equal(saved, []string{"buckets"}) {
+		t.Errorf("Unexpected checkpoint %v", saved)
+	}
+
+	// ----------
+	// Store errors do not fail the Q.
+
+	rq = build(failCheckpoints{}, Satisfied)
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if len(ran) != 4 || rq.CheckpointErr() == nil {
+		t.Errorf("Expected every task to run (and a store error); got %v / %v", ran, rq.CheckpointErr())
+	}
+
+	if err := rq.AlwaysRun("typo"); !errors.Is(err, ErrQNoTask) {
+		t.Errorf("Expected ErrQNoTask; got %v", err)
+	}
+
+}
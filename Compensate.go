@@ -0,0 +1,106 @@
+package initq
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+/*
+	Compensation:
+
+	- A task may have a compensation function that undoes what the task did
+	  (removes a temp dir, releases a lock, de-registers a service).
+	- When processing ends with a Stop, a panic in a task function, or a
+	  canceled (or timed out) context, the compensation functions of the
+	  tasks Satisfied in that process run are run - in the reverse of the
+	  order the tasks were Satisfied. Tasks without a compensation function
+	  are left as is.
+	- Tasks Satisfied in an earlier process run (such as those left in
+	  place by ResetTask or a Supervisor restart) or restored from a
+	  checkpoint are not compensated.
+	- A compensated task is returned to the initial (UnRun) state, so that
+	  it is run again when the Q is processed again.
+	- Errors from compensation functions are joined (errors.Join) to the
+	  error of the process run. The result still matches ErrQStopped (or
+	  ErrQCanceled) with errors.Is. Without compensation errors, the error
+	  is unchanged.
+*/
+
+/* ======================================================================== */
+
+// SetCompensation sets the compensation function of the named task. See
+// the notes (above) for when it is called.
+//
+// An error (ErrQNoTask) is returned if the name does not match a task.
+func (rq *InitQ) SetCompensation(name string, f func() error) (err error) {
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	rqi := rq.item(name)
+	if rqi == nil {
+		return fmt.Errorf("%w: %s", ErrQNoTask, name)
+	}
+
+	rqi.compensation = f
+
+	return
+}
+
+/* ======================================================================== */
+
+// compensate runs the compensation functions of the tasks Satisfied in this
+// process run (in reverse order of completion). The return is the cause
+// joined with any compensation errors. It must be called with the lock held.
+// (The lock is released while each function runs.)
+func (rq *InitQ) compensate(cause error) error {
+
+	errs := []error{cause}
+	undone := false
+
+	completed := rq.completed()
+	slices.Reverse(completed)
+
+	for _, name := range completed {
+
+		// The item may have been compensated (by another goroutine) while
+		// the lock was released.
+		rqi := rq.item(name)
+		if rqi.compensation == nil || rqi.state != Satisfied || rqi.seq <= rq.runSeq {
+			continue
+		}
+
+		// The item is reset first, so that it is only compensated once.
+		f := rqi.compensation
+		rqi.reset()
+		undone = true
+
+		if cerr := rq.unlocked(f); cerr != nil {
+			errs = append(errs, fmt.Errorf("compensation of %s: %w", name, cerr))
+		}
+	}
+
+	// Compensated tasks are no longer Satisfied (in the checkpoint).
+	if undone {
+		rq.saveCheckpoint()
+	}
+
+	if len(errs) == 1 {
+		return cause
+	}
+
+	return errors.Join(errs...)
+}
+
+/* ======================================================================== */
+
+// unlocked calls the function without the lock held. It must be called with
+// the lock held (that is re-acquired even if the function panics).
+func (rq *InitQ) unlocked(f func() error) error {
+
+	rq.mu.Unlock()
+	defer rq.mu.Lock()
+
+	return f()
+}
@@ -0,0 +1,207 @@
+package initq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+/* ======================================================================== */
+
+func TestCompensate(t *testing.T) {
+
+	var rq *InitQ
+	var undone []string
+
+	// undo returns a compensation function that records the task.
+	undo := func(name string, err error) func() error {
+		return func() error {
+			undone = append(undone, name)
+			return err
+		}
+	}
+
+	ok := func() ReqResult { return Satisfied }
+
+	// build is a Q of three compensated tasks (and one that is not), that
+	// ends with the last task.
+	build := func(last QFunc) *InitQ {
+
+		rq := NewInitQ()
+
+		rq.Add("tmpdir", ok)
+		rq.Add("lock", ok, "tmpdir")
+		rq.Add("plain", ok, "lock")
+		rq.Add("register", ok, "plain")
+		rq.Add("last", last, "register")
+
+		for _, name := range []string{"tmpdir", "lock", "register"} {
+			if err := rq.SetCompensation(name, undo(name, nil)); err != nil {
+				t.Errorf("Unexpected error %s", err.Error())
+			}
+		}
+
+		undone = nil
+
+		return rq
+	}
+
+	// ----------
+	// A Stop compensates in reverse order of completion.
+
+	rq = build(func() ReqResult { return Stop })
+
+	if err := rq.Process(); err != ErrQStopped {
+		t.Errorf("Expected ErrQStopped; got %v", err)
+	}
+
+	if !slices.Equal(undone, []string{"register", "lock", "tmpdir"}) {
+		t.Errorf("Unexpected compensation order %v", undone)
+	}
+
+	states := rq.States()
+	if states["tmpdir"] != UnRun || states["plain"] != Satisfied {
+		t.Errorf("Unexpected states %v", states)
+	}
+
+	// ----------
+	// A successful Q is not compensated.
+
+	rq = build(ok)
+
+	if err := rq.Process(); err != nil || len(undone) != 0 {
+		t.Errorf("Unexpected compensation %v (%v)", undone, err)
+	}
+
+	// ----------
+	// Compensation errors are joined.
+
+	rq = build(func() ReqResult { return Stop })
+	rq.SetCompensation("lock", undo("lock", fmt.Errorf("lock is gone")))
+	rq.SetCompensation("tmpdir", undo("tmpdir", fmt.Errorf("dir is busy")))
+
+	err := rq.Process()
+
+	if !errors.Is(err, ErrQStopped) || !strings.Contains(err.Error(), "lock is gone") || !strings.Contains(err.Error(), "dir is busy") {
+		t.Errorf("Expected joined errors; got %v", err)
+	}
+
+	if len(undone) != 3 {
+		t.Errorf("Expected all compensation to run; got %v", undone)
+	}
+
+	// ----------
+	// Cancellation.
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	rq = build(ok)
+	rq.Add("cancel", func() ReqResult { cancel(); return Satisfied }, "register")
+	rq.Add("after", ok, "cancel")
+
+	if err := rq.ProcessContext(ctx); !errors.Is(err, ErrQCanceled) {
+		t.Errorf("Expected ErrQCanceled; got %v", err)
+	}
+
+	if len(undone) != 3 {
+		t.Errorf("Expected compensation on cancel; got %v", undone)
+	}
+
+	// ----------
+	// A panic (that is re-raised).
+
+	rq = build(func() ReqResult { panic("boom") })
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("Expected the panic to be re-raised; got %v", r)
+			}
+		}()
+		rq.Process()
+	}()
+
+	if len(undone) != 3 {
+		t.Errorf("Expected compensation on panic; got %v", undone)
+	}
+
+	// ----------
+	// Only the tasks Satisfied in the run that failed are compensated - not
+	// those left in place from an earlier run (ResetTask).
+
+	stop := false
+	rq = build(func() ReqResult {
+		if stop {
+			return Stop
+		}
+		return Satisfied
+	})
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	stop = true
+	if err := rq.ResetTask("register", true); err != nil {
+		t.Errorf("Unexpected error %s", err.Error())
+	}
+
+	if err := rq.Process(); err != ErrQStopped {
+		t.Errorf("Expected ErrQStopped; got %v", err)
+	}
+
+	if !slices.Equal(undone, []string{"register"}) {
+		t.Errorf("Expected only register to be compensated; got %v", undone)
+	}
+
+	if states := rq.States(); states["tmpdir"] != Satisfied || states["lock"] != Satisfied {
+		t.Errorf("Expected earlier tasks to remain Satisfied; got %v", states)
+	}
+
+	// ----------
+	// The same for a Supervisor restart (of a task that then stops).
+
+	stop = false
+	rq = build(func() ReqResult {
+		if stop {
+			return Stop
+		}
+		return Satisfied
+	})
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	sup := NewSupervisor(rq, OneForOne)
+	sup.Watch("last", func() error {
+		if !stop {
+			stop = true
+			return fmt.Errorf("last failed")
+		}
+		return nil
+	}, nil)
+
+	if err := sup.Check(); err != ErrQStopped {
+		t.Errorf("Expected ErrQStopped; got %v", err)
+	}
+
+	if len(undone) != 0 {
+		t.Errorf("Expected no compensation of healthy tasks; got %v", undone)
+	}
+
+	if states := rq.States(); states["tmpdir"] != Satisfied || states["register"] != Satisfied {
+		t.Errorf("Expected healthy tasks to remain Satisfied; got %v", states)
+	}
+
+	// ----------
+	// Unknown names.
+
+	if err := rq.SetCompensation("typo", undo("typo", nil)); !errors.Is(err, ErrQNoTask) {
+		t.Errorf("Expected ErrQNoTask; got %v", err)
+	}
+
+}
@@ -0,0 +1,129 @@
+package initq
+
+import (
+	"slices"
+	"time"
+)
+
+/* ------------------------------------------------------------------------ */
+
+// EventKind identifies the type of an Event.
+type EventKind int
+
+/* ------------------------------------------------------------------------ */
+
+const (
+	// EventProcessStart is sent once the Q has passed the label and
+	// dependency checks - before the first pass.
+	EventProcessStart EventKind = iota
+
+	// EventTaskBlocked is sent when a task is skipped (in a pass) because
+	// its explicit dependencies are not yet Satisfied.
+	EventTaskBlocked
+
+	// EventTaskStart is sent immediately before a task function is called.
+	EventTaskStart
+
+	// EventTaskEnd is sent when a task function returns. Result and Elapsed
+	// are set.
+	EventTaskEnd
+
+	// EventPassEnd is sent at the end of each pass of the Q.
+	EventPassEnd
+
+	// EventProcessEnd is sent when processing ends (for any reason). Err is
+	// the error that will be returned - or ErrQPanicked (wrapping the
+	// value) when a task function panicked.
+	EventProcessEnd
+)
+
+/* ------------------------------------------------------------------------ */
+
+// Event describes a change in the processing of a Q. Events are sent (in
+// order) to Observers.
+type Event struct {
+	// Kind is the type of event.
+	Kind EventKind
+
+	// Time is when the event happened.
+	Time time.Time
+
+	// Task is the task label (for task events).
+	Task string
+
+	// Pass is the current (1-based) pass of the Q.
+	Pass int
+
+	// Result is the task function result (EventTaskEnd).
+	Result ReqResult
+
+	// Elapsed is the time in the task function (EventTaskEnd).
+	Elapsed time.Duration
+
+	// Satisfied is the number of tasks Satisfied (at the time of the event).
+	Satisfied int
+
+	// Total is the number of tasks in the Q.
+	Total int
+
+	// Pending is the labels of the tasks not (yet) Satisfied, in the order
+	// they were added (EventProcessStart).
+	Pending []string
+
+	// Err is the result of processing (EventProcessEnd).
+	Err error
+}
+
+/* ------------------------------------------------------------------------ */
+
+// Observer receives Events from a processing Q. Observers are called from
+// the goroutine processing the Q, and should return quickly. They are called
+// without the Q lock held, so they may query the Q (such as with Status).
+type Observer func(ev Event)
+
+/* ======================================================================== */
+
+// Observe adds an Observer to the Q. All observers receive all events.
+func (rq *InitQ) Observe(o Observer) {
+
+	if rq == nil || o == nil {
+		return
+	}
+
+	rq.mu.Lock()
+	rq.observers = append(rq.observers, o)
+	rq.mu.Unlock()
+}
+
+/* ======================================================================== */
+
+// emit sends an event to all observers. It must be called with the lock
+// held. The common fields (time, pass, counts) are filled in here. The lock
+// is released while the observers are called.
+func (rq *InitQ) emit(ev Event) {
+
+	if len(rq.observers) == 0 {
+		return
+	}
+
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+
+	ev.Pass = rq.passes + 1
+	ev.Total = len(rq.q)
+	for _, rqi := range rq.q {
+		if rqi.state == Satisfied {
+			ev.Satisfied++
+		}
+	}
+
+	observers := slices.Clone(rq.observers)
+
+	rq.mu.Unlock()
+	defer rq.mu.Lock()
+
+	for _, o := range observers {
+		o(ev)
+	}
+}
@@ -0,0 +1,161 @@
+package initq
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+/*
+	Collecting failures:
+
+	- By default, the first task that returns Stop ends processing (with
+	  ErrQStopped).
+	- With SetCollectFailures, a task that returns Stop is recorded as a
+	  failure, and processing continues with the tasks that do not depend
+	  on it. Tasks with an explicit dependency on (or that are WaitingOn) a
+	  failed task - directly or indirectly - are skipped.
+	- Once nothing more can run, the returned error joins (errors.Join)
+	  ErrQStopped with a TaskError for every failure - in the order they
+	  failed. Tasks that depend on a failed task by 'sense' cannot be known,
+	  and (if they are left unsatisfied) the QUnresolvable is joined too.
+	- A task may return Stop with an error using Task.Fail. That error is
+	  the Err of its TaskError (otherwise it is ErrQStopped).
+*/
+
+/* ------------------------------------------------------------------------ */
+
+// TaskError is the failure of a single task.
+type TaskError struct {
+	// Task is the task label.
+	Task string
+
+	// Err is the error given to Task.Fail - or ErrQStopped if the task
+	// simply returned Stop.
+	Err error
+}
+
+/* ======================================================================== */
+
+// Error satisfies the error interface.
+func (te *TaskError) Error() string {
+	return fmt.Sprintf("task %s: %s", te.Task, te.Err.Error())
+}
+
+/* ======================================================================== */
+
+// Unwrap returns the underlying error.
+func (te *TaskError) Unwrap() error {
+	return te.Err
+}
+
+/* ======================================================================== */
+
+// Fail records the error of the task, and returns Stop.
+func (t *Task) Fail(err error) ReqResult {
+
+	t.err = err
+
+	return Stop
+}
+
+/* ======================================================================== */
+
+// SetCollectFailures enables (true) collecting all failures, rather than
+// ending processing on the first Stop. See the notes (above).
+//
+// It must not be called while the Q is processing.
+func (rq *InitQ) SetCollectFailures(collect bool) {
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	rq.collect = collect
+}
+
+/* ======================================================================== */
+
+// fail records the failure of the (stopped) item. The return is the error
+// that ends processing - or nil when failures are collected. It must be
+// called with the lock held.
+func (rq *InitQ) fail(rqi *initQItem) error {
+
+	te := &TaskError{Task: rqi.name, Err: rqi.err}
+	if te.Err == nil {
+		te.Err = ErrQStopped
+	}
+
+	rq.failures = append(rq.failures, te)
+
+	if rq.collect {
+		return nil
+	}
+
+	return rq.failed()
+}
+
+/* ======================================================================== */
+
+// failed returns the error of the failures (joined with the other errors).
+// When failures are not collected, and the task that stopped gave no error,
+// this is ErrQStopped alone. It must be called with the lock held.
+func (rq *InitQ) failed(others ...error) error {
+
+	errs := []error{ErrQStopped}
+	for _, te := range rq.failures {
+		if te.Err != ErrQStopped || rq.collect {
+			errs = append(errs, te)
+		}
+	}
+	errs = append(errs, others...)
+
+	if len(errs) == 1 {
+		return ErrQStopped
+	}
+
+	return errors.Join(errs...)
+}
+
+/* ======================================================================== */
+
+// excluded reports if the item is not to be run again in this process run
+// - because it failed, or because it depends on a task that failed. Only
+// failures that are collected exclude tasks. It must be called with the lock
+// held.
+func (rq *InitQ) excluded(rqi *initQItem) bool {
+
+	if !rq.collect || len(rq.failures) == 0 {
+		return false
+	}
+
+	return rq.doomed(rqi.name, make(map[string]bool))
+}
+
+/* ======================================================================== */
+
+// doomed reports if the named task failed, or (through its dependencies and
+// waits) depends on a task that failed.
+func (rq *InitQ) doomed(name string, seen map[string]bool) bool {
+
+	if seen[name] {
+		return false
+	}
+	seen[name] = true
+
+	if slices.ContainsFunc(rq.failures, func(te *TaskError) bool { return te.Task == name }) {
+		return true
+	}
+
+	rqi := rq.item(name)
+	if rqi == nil {
+		return false
+	}
+
+	for _, dep := range slices.Concat(rqi.deps, rqi.waits) {
+		if !rq.satisfied(dep) && rq.doomed(dep, seen) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,129 @@
+package initq
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+/* ======================================================================== */
+
+func TestFailures(t *testing.T) {
+
+	var rq *InitQ
+	var ran []string
+	var mu sync.Mutex
+
+	// task returns a task function that records that it ran.
+	task := func(name string, r ReqResult) QFunc {
+		return func() ReqResult {
+			mu.Lock()
+			defer mu.Unlock()
+			ran = append(ran, name)
+			return r
+		}
+	}
+
+	// taskErrors returns the TaskErrors joined in the error.
+	taskErrors := func(err error) (names []string) {
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range joined.Unwrap() {
+				if te, ok := e.(*TaskError); ok {
+					names = append(names, te.Task)
+				}
+			}
+		}
+		return
+	}
+
+	// build is a Q with two failures in independent branches.
+	build := func(parallel int) *InitQ {
+
+		rq := NewInitQ()
+		rq.SetCollectFailures(true)
+		rq.SetParallel(parallel)
+
+		rq.AddTask("config", func(t *Task) ReqResult { return t.Fail(errors.New("bad config")) })
+		rq.Add("server", task("server", Satisfied), "config")
+		rq.AddTask("report", func(t *Task) ReqResult { return t.WaitingOn("server") })
+		rq.Add("cert", task("cert", Stop))
+		rq.Add("log", task("log", Satisfied))
+		rq.Add("metrics", task("metrics", Satisfied), "log")
+
+		ran = nil
+
+		return rq
+	}
+
+	// ----------
+	// All failures are reported, and dependants are skipped.
+
+	for _, parallel := range []int{0, 4} {
+
+		rq = build(parallel)
+
+		err := rq.Process()
+
+		if !errors.Is(err, ErrQStopped) || !strings.Contains(err.Error(), "bad config") {
+			t.Errorf("Expected the joined failures; got %v", err)
+		}
+
+		if names := taskErrors(err); !slices.Equal(names, []string{"config", "cert"}) && !slices.Equal(names, []string{"cert", "config"}) {
+			t.Errorf("Expected config and cert failures; got %v", names)
+		}
+
+		var te *TaskError
+		if !errors.As(err, &te) {
+			t.Errorf("Expected a *TaskError")
+		}
+
+		if slices.Contains(ran, "server") {
+			t.Errorf("A dependant of a failed task was run")
+		}
+
+		states := rq.States()
+		if states["log"] != Satisfied || states["metrics"] != Satisfied {
+			t.Errorf("Expected the independent tasks to be Satisfied; got %v", states)
+		}
+	}
+
+	// ----------
+	// Without collecting, the first failure (and its error) ends the Q.
+
+	rq = build(0)
+	rq.SetCollectFailures(false)
+
+	err := rq.Process()
+
+	if !errors.Is(err, ErrQStopped) || !slices.Equal(taskErrors(err), []string{"config"}) {
+		t.Errorf("Expected the config failure alone; got %v", err)
+	}
+
+	if slices.Contains(ran, "log") {
+		t.Errorf("A task was run after the Stop")
+	}
+
+	// ----------
+	// Dependants by 'sense' are unresolved - and reported with the failures.
+
+	rq = NewInitQ()
+	rq.SetCollectFailures(true)
+
+	rq.Add("config", task("config", Stop))
+	rq.Add("server", func() ReqResult {
+		if rq.States()["config"] != Satisfied {
+			return TryAgain
+		}
+		return Satisfied
+	})
+
+	err = rq.TryProcess()
+
+	var qur *QUnresolvable
+	if !errors.Is(err, ErrQStopped) || !errors.As(err, &qur) || !slices.Equal(qur.UnresolvedTasks(), []string{"server"}) {
+		t.Errorf("Expected a failure and an unresolved task; got %v", err)
+	}
+
+}
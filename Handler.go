@@ -0,0 +1,133 @@
+package initq
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+/* ------------------------------------------------------------------------ */
+
+// handlerBody is the JSON body of all Handler responses.
+type handlerBody struct {
+	// Ready is true when all tasks are Satisfied.
+	Ready bool `json:"ready"`
+
+	// Pending are the tasks that are not Satisfied (UnRun, TryAgain or
+	// Stop). It is omitted when empty.
+	Pending []TaskStatus `json:"pending,omitempty"`
+
+	// Tasks are all tasks (in the all-task and per-task responses).
+	Tasks []TaskStatus `json:"tasks,omitempty"`
+
+	// Error describes why the request failed (such as an unknown task). It
+	// is omitted when empty.
+	Error string `json:"error,omitempty"`
+}
+
+/* ======================================================================== */
+
+// NewHandler returns an http.Handler that reports the state of the Q. It is
+// intended for readiness and liveness probes (such as in Kubernetes). All
+// responses are JSON.
+//
+//	GET /readyz        200 when all tasks are Satisfied, otherwise 503 with
+//	                   the pending (UnRun, TryAgain, Stop) tasks.
+//	GET /livez         200 unless a task has Stopped (failed), then 503.
+//	GET /tasks         200 with the status of every task.
+//	GET /tasks/{name}  200 when the task is Satisfied, otherwise 503. 404
+//	                   (with an error) if no task matches the name.
+//
+// The paths are relative to where the handler is mounted. Use
+// http.StripPrefix to mount it under a sub-path.
+func NewHandler(rq *InitQ) http.Handler {
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+
+		var body handlerBody
+
+		for _, ts := range rq.Status() {
+			if ts.State != Satisfied {
+				body.Pending = append(body.Pending, ts)
+			}
+		}
+
+		body.Ready = len(body.Pending) == 0
+		writeStatus(w, body.Ready, body)
+	})
+
+	mux.HandleFunc("GET /livez", func(w http.ResponseWriter, r *http.Request) {
+
+		var body handlerBody
+		live := true
+
+		for _, ts := range rq.Status() {
+			if ts.State != Satisfied {
+				body.Pending = append(body.Pending, ts)
+			}
+			if ts.State == Stop {
+				live = false
+			}
+		}
+
+		body.Ready = len(body.Pending) == 0
+		writeStatus(w, live, body)
+	})
+
+	mux.HandleFunc("GET /tasks", func(w http.ResponseWriter, r *http.Request) {
+
+		var body handlerBody
+
+		body.Tasks = rq.Status()
+		body.Ready = true
+		for _, ts := range body.Tasks {
+			if ts.State != Satisfied {
+				body.Ready = false
+			}
+		}
+
+		writeStatus(w, true, body)
+	})
+
+	mux.HandleFunc("GET /tasks/{name}", func(w http.ResponseWriter, r *http.Request) {
+
+		name := r.PathValue("name")
+
+		for _, ts := range rq.Status() {
+			if ts.Name == name {
+				body := handlerBody{Ready: ts.State == Satisfied, Tasks: []TaskStatus{ts}}
+				writeStatus(w, body.Ready, body)
+				return
+			}
+		}
+
+		writeJSON(w, http.StatusNotFound, handlerBody{Error: "no such task in run Q: " + name})
+	})
+
+	return mux
+}
+
+/* ======================================================================== */
+
+// writeStatus writes the JSON body with a 200 (ok) or 503 (!ok) status.
+func writeStatus(w http.ResponseWriter, ok bool, body handlerBody) {
+
+	if ok {
+		writeJSON(w, http.StatusOK, body)
+	} else {
+		writeJSON(w, http.StatusServiceUnavailable, body)
+	}
+}
+
+/* ======================================================================== */
+
+// writeJSON writes the JSON body with the status code.
+func writeJSON(w http.ResponseWriter, code int, body handlerBody) {
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	// There is no recourse for a failed write.
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,106 @@
+package initq
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+/* ======================================================================== */
+
+func TestHandler(t *testing.T) {
+
+	var rq *InitQ
+
+	// get issues a request and decodes the (JSON) response.
+	get := func(h http.Handler, path string) (code int, body handlerBody) {
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Unexpected content type %s from %s", ct, path)
+		}
+
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Errorf("Invalid JSON from %s - %s", path, err.Error())
+		}
+
+		return rec.Code, body
+	}
+
+	// ----------
+	// Before processing - not ready, but live.
+
+	rq = NewInitQ()
+	gate := make(chan struct{})
+
+	rq.Add("cmdline", func() ReqResult { return Satisfied })
+	rq.Add("config", func() ReqResult {
+		<-gate
+		return Satisfied
+	}, "cmdline")
+
+	h := NewHandler(rq)
+
+	code, body := get(h, "/readyz")
+	if code != http.StatusServiceUnavailable || body.Ready || len(body.Pending) != 2 {
+		t.Errorf("Expected not ready with 2 pending; got %d %+v", code, body)
+	}
+
+	if code, _ := get(h, "/livez"); code != http.StatusOK {
+		t.Errorf("Expected live; got %d", code)
+	}
+
+	// ----------
+	// While processing (config is blocked). The handler must not block.
+
+	done := make(chan error)
+	go func() { done <- rq.Process() }()
+
+	for {
+		if code, _ := get(h, "/tasks/cmdline"); code == http.StatusOK {
+			break
+		}
+	}
+
+	code, body = get(h, "/readyz")
+	if code != http.StatusServiceUnavailable || len(body.Pending) != 1 || body.Pending[0].Name != "config" {
+		t.Errorf("Expected config pending; got %d %+v", code, body)
+	}
+
+	close(gate)
+	if err := <-done; err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	// ----------
+	// Complete.
+
+	if code, body := get(h, "/readyz"); code != http.StatusOK || !body.Ready {
+		t.Errorf("Expected ready; got %d %+v", code, body)
+	}
+
+	code, body = get(h, "/tasks")
+	if code != http.StatusOK || len(body.Tasks) != 2 || body.Tasks[1].State != Satisfied {
+		t.Errorf("Unexpected task list %d %+v", code, body)
+	}
+
+	if code, body := get(h, "/tasks/nope"); code != http.StatusNotFound || body.Error == "" || body.Ready {
+		t.Errorf("Expected 404 (with an error) for an unknown task; got %d %+v", code, body)
+	}
+
+	// ----------
+	// A stopped Q is not live.
+
+	rq = NewInitQ()
+	rq.Add("stopper", func() ReqResult { return Stop })
+	_ = rq.Process()
+
+	code, body = get(NewHandler(rq), "/livez")
+	if code != http.StatusServiceUnavailable || body.Pending[0].State != Stop {
+		t.Errorf("Expected not live; got %d %+v", code, body)
+	}
+
+}
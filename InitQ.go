@@ -50,10 +50,14 @@
 package initq
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"slices"
 	"strings"
+	"sync"
+	"time"
 )
 
 /* ------------------------------------------------------------------------ */
@@ -73,6 +77,72 @@ type InitQ struct {
 	// the Process call. The intent is to keep Add calls 'clean', yet still
 	// capture failures in a testable manner.
 	addErr string
+
+	// trace is the record of each task function invocation from the last
+	// process run.
+	trace []Attempt
+
+	// passes is the number of passes of the Q in the last process run.
+	passes int
+
+	// seq is the count of items Satisfied. It is used to record the order
+	// of completion (across process runs). runSeq is seq at the start of the
+	// process run - the items Satisfied in the run have a higher seq.
+	seq    int
+	runSeq int
+
+	// observers receive process events.
+	observers []Observer
+
+	// running are the tasks whose functions are currently running.
+	running []string
+
+	// scheduler determines when TryAgain tasks are called again. progress
+	// is the count of tasks Satisfied, and skipped the count of calls that
+	// the scheduler skipped, in the last process run.
+	scheduler Scheduler
+	progress  int
+	skipped   int
+
+	// maxPasses (when set) is the pass budget, and stallPasses the number
+	// of passes without progress that abandon processing.
+	maxPasses   int
+	stallPasses int
+
+	// parallel is the number of task functions that may run at once. The
+	// limits are the named limits, and inUse the count of running tasks
+	// that use each.
+	parallel int
+	limits   map[string]int
+	inUse    map[string]int
+
+	// collect (see SetCollectFailures) keeps processing after a Stop. The
+	// failures are of the last process run.
+	collect  bool
+	failures []*TaskError
+
+	// checkpoints (when set) persists the Satisfied tasks. The checkpoint is
+	// restored once (checkpointed), and restored are the tasks that were.
+	checkpoints   CheckpointStore
+	checkpointed  bool
+	restored      []string
+	checkpointErr error
+
+	// orderStore (when set) persists the learned order. The learned order
+	// and savings (and store error) are from the last process run. rank is
+	// the position of each task in the learned order (used by schedule -
+	// the Q itself keeps the order the tasks were added).
+	orderStore OrderStore
+	learned    LearnedOrder
+	rank       map[string]int
+	saved      int
+	orderErr   error
+
+	// mu protects the Q (and the state of its items). Task functions are
+	// called without it held - so that they may call Add, and so that the
+	// state of the Q may be read (such as by a status handler in another
+	// goroutine) while it is processing.
+	mu sync.Mutex
 }
 
 /* ======================================================================== */
@@ -102,8 +172,29 @@ func NewInitQ() (rq *InitQ) {
 //
 // The final (optional) parameters are a means of expressing dependent
 // required tasks if completion cannot be derived from the environment.
+//
+// Add may be called by a task function while the Q is processing (to add
+// child tasks that are only known once the task runs). Added tasks are run
+// starting with the next pass, and are checked (for labels / dependencies)
+// once the pass completes. A task that adds tasks with dependencies on each
+// other should add all of them before it returns.
 func (rq *InitQ) Add(name string, f QFunc, deps ...string) {
 
+	// A nil QFunc is left as a nil TaskFunc (so that it fails the check).
+	var tf TaskFunc
+	if f != nil {
+		tf = func(*Task) ReqResult { return f() }
+	}
+
+	rq.AddTask(name, tf, deps...)
+}
+
+/* ======================================================================== */
+
+// AddTask is Add for a task function that takes a Task handle (see
+// WaitingOn). The name and dependencies are the same as the Add method.
+func (rq *InitQ) AddTask(name string, f TaskFunc, deps ...string) {
+
 	// Fatal on misuse is appropriate.
 	// This is better than letting the user think things went ok when they
 	// did not. This is not a random runtime fatal error, but one that is
@@ -114,7 +205,7 @@ func (rq *InitQ) Add(name string, f QFunc, deps ...string) {
 
 	// Check inputs.
 	if len(name) == 0 {
-		rq.addErr = "Add called with an empty name label."
+		rq.setAddErr("Add called with an empty name label.")
 		if BehaveUnresolvIsErr {
 			return
 		}
@@ -123,7 +214,7 @@ func (rq *InitQ) Add(name string, f QFunc, deps ...string) {
 
 	// A function reference must be passed.
 	if f == nil {
-		rq.addErr = fmt.Sprintf("Add(%s) called with a nil function.", name)
+		rq.setAddErr(fmt.Sprintf("Add(%s) called with a nil function.", name))
 		if BehaveUnresolvIsErr {
 			return
 		}
@@ -133,7 +224,7 @@ func (rq *InitQ) Add(name string, f QFunc, deps ...string) {
 	// None of the deps should self-reference.
 	for _, d := range deps {
 		if d == name {
-			rq.addErr = fmt.Sprintf("Add(%s) called with a self-referencing dependency.", name)
+			rq.setAddErr(fmt.Sprintf("Add(%s) called with a self-referencing dependency.", name))
 			if BehaveUnresolvIsErr {
 				return
 			}
@@ -142,8 +233,11 @@ func (rq *InitQ) Add(name string, f QFunc, deps ...string) {
 	}
 
 	// Initialize and append to the Q.
-	rqi := newInitQItem(name, f, deps...)
+	rqi := newTaskItem(name, f, deps...)
+
+	rq.mu.Lock()
 	rq.q = append(rq.q, rqi)
+	rq.mu.Unlock()
 
 }
 
@@ -158,7 +252,17 @@ func (rq *InitQ) Add(name string, f QFunc, deps ...string) {
 // error and) returns the Stop value.
 func (rq *InitQ) Process() (err error) {
 	// The default behaviour.
-	return rq.process(false)
+	return rq.process(context.Background(), false)
+}
+
+/* ======================================================================== */
+
+// ProcessContext is Process with a context. When the context is canceled (or
+// times out) no further tasks are run, and an error wrapping both
+// ErrQCanceled and the cause of the cancellation is returned. A task that is
+// running when the context is canceled is allowed to finish.
+func (rq *InitQ) ProcessContext(ctx context.Context) (err error) {
+	return rq.process(ctx, false)
 }
 
 /* ======================================================================== */
@@ -175,7 +279,15 @@ func (rq *InitQ) Process() (err error) {
 // Process() variant.
 func (rq *InitQ) TryProcess() (err error) {
 	// The modified behaviour.
-	return rq.process(true)
+	return rq.process(context.Background(), true)
+}
+
+/* ======================================================================== */
+
+// TryProcessContext is TryProcess with a context. Cancellation is handled as
+// described in ProcessContext.
+func (rq *InitQ) TryProcessContext(ctx context.Context) (err error) {
+	return rq.process(ctx, true)
 }
 
 /* ======================================================================== */
@@ -184,8 +296,8 @@ func (rq *InitQ) TryProcess() (err error) {
 // takes a boolean to enable (true) the return of a dedicated error, rather
 // than a log.Fatal(). The error is comparable, so will not have distinct
 // messaging about why the Q could not be satisfied, and the caller will need
-// to handle that
-func (rq *InitQ) process(unsatIsError bool) (err error) {
+// to handle that. The context is checked before each task is run.
+func (rq *InitQ) process(ctx context.Context, unsatIsError bool) (err error) {
 
 	// Fatal is appropriate.
 	// Discussion on *why* is in the Add method.
@@ -193,6 +305,11 @@ func (rq *InitQ) process(unsatIsError bool) (err error) {
 		log.Fatal("Method Process called on a nil function.")
 	}
 
+	// The lock is held for the duration - except while task functions are
+	// called (see invoke).
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
 	// Handle any errors that may have been created. There is no need to test
 	// the behaviour as that is the only way this internal error message is
 	// set.
@@ -203,97 +320,133 @@ func (rq *InitQ) process(unsatIsError bool) (err error) {
 		return fmt.Errorf("%s", rq.addErr)
 	}
 
-	// Check to see if any dependencies are 'dangling'. This is the case
-	// where a 'semaphore' dependency references a task that does not exist.
-	// This cannot be checked in the Add calls.
-	// First build a simpler lookup list.
-	validLabels := make([]string, 0)
-	for _, task := range rq.q {
-
-		// This part *could* be done in Add - but easier here.
-		if slices.Contains(validLabels, task.name) {
-
-			fatalMsg := fmt.Sprintf("The %s task label was used more than once.", task.name)
-			if BehaveUnresolvIsErr {
-				// This is unreachable under normal circumstances.
-				return fmt.Errorf("%s", fatalMsg)
-			}
-			log.Fatalf("%s", fatalMsg)
+	// Label and dependency sanity checks.
+	if fatalMsg := rq.validate(); len(fatalMsg) > 0 {
+		if BehaveUnresolvIsErr {
+			// This is unreachable under normal circumstances.
+			return fmt.Errorf("%s", fatalMsg)
 		}
+		log.Fatalf("%s", fatalMsg)
+	}
 
-		validLabels = append(validLabels, task.name)
+	// The trace (and pass count) are of the last run only.
+	rq.trace = make([]Attempt, 0)
+	rq.passes = 0
+	rq.progress = 0
+	rq.skipped = 0
+	rq.failures = nil
+
+	// Every task is called (at least) once per run - by any scheduler.
+	for _, rqi := range rq.q {
+		rqi.heard = -1
+		clear(rqi.limitWaits)
 	}
-	// Now walk all dependencies looking for solid matches.
-	for _, task := range rq.q {
-		for _, dep := range task.deps {
-			if !slices.Contains(validLabels, dep) {
-				fatalMsg := fmt.Sprintf("Task %s has dependency %s that does not match any existing task.", task.name, dep)
-				if BehaveUnresolvIsErr {
-					// This is unreachable under normal circumstances.
-					return fmt.Errorf("%s", fatalMsg)
-				}
-				log.Fatalf("%s", fatalMsg)
-			}
+
+	// Use the learned order (if enabled) for the passes of this run.
+	rq.applyOrder()
+
+	// Resume from the checkpoint (if enabled).
+	rq.restoreCheckpoint()
+
+	// Only the tasks Satisfied from here on are compensated.
+	rq.runSeq = rq.seq
+
+	// The start event lists the tasks left to run (after the checkpoint).
+	var pending []string
+	for _, rqi := range rq.q {
+		if rqi.state != Satisfied {
+			pending = append(pending, rqi.name)
 		}
 	}
-	// End of dependency / label sanity checks.
 
-	passes := 0
-	qlen := len(rq.q)
+	// Observers are told of the start, and (however it happens) the end.
+	// The deferred emit runs before the deferred unlock. A panic in a task
+	// function is reported (as ErrQPanicked) and then re-raised.
+	rq.emit(Event{Kind: EventProcessStart, Pending: pending})
+	defer func() {
+		if r := recover(); r != nil {
+			rq.emit(Event{Kind: EventProcessEnd, Err: panicErr(r)})
+			panic(r)
+		}
+		rq.emit(Event{Kind: EventProcessEnd, Err: err})
+	}()
+
+	// A Q that ends part way (stopped, canceled, or a panic in a task) is
+	// compensated (see SetCompensation). This runs before the end event.
+	defer func() {
+		if r := recover(); r != nil {
+			rq.compensate(nil)
+			panic(r)
+		}
+		if errors.Is(err, ErrQStopped) || errors.Is(err, ErrQCanceled) {
+			err = rq.compensate(err)
+		}
+	}()
+
+	// The number of passes (in a row) that made no progress.
+	idle := 0
+	stalled := false
 
 	// The top loop drops us out when we have exceeded the maximum possible
 	// passes.
-	for passes <= qlen {
+	for rq.passes < rq.passBudget() {
+
+		// Tasks may Add (child) tasks while the Q is processing. These are
+		// not run until the next pass.
+		qlen := len(rq.q)
+		before := rq.progress
+
+		// Run a pass of the InitQ. The Q is satisfied - unless shown
+		// otherwise.
+		var satisfied bool
+		if satisfied, err = rq.pass(ctx); err != nil {
+			return
+		}
 
-		// Assume the Q has been satisfied - unless shown otherwise.
-		satisfied := true
+		rq.emit(Event{Kind: EventPassEnd})
+		rq.passes++
 
-		// The next loop is a pass of the InitQ.
-		for _, rqi := range rq.q {
+		// An Add call (from a task) failed.
+		if len(rq.addErr) > 0 {
+			return fmt.Errorf("%s", rq.addErr)
+		}
 
-			// Check for dependencies.
-			allDepsGood := true
-			for _, dep := range rqi.deps {
-				if rq.satisfied(dep) == false {
-					allDepsGood = false
-				}
-			}
+		// Tasks were added during the pass. These have not run (so the Q is
+		// not satisfied), and must be checked as if they were added before
+		// processing. The pass budget grows with the Q.
+		if len(rq.q) > qlen {
 
-			if allDepsGood == false {
-				rqi.state = TryAgain
-				satisfied = false
-				continue
-			}
+			satisfied = false
 
-			// "run" each item. If previously satisfied, the run will be
-			// skipped. We only care about the 'unsatisfied' cases (that prove
-			// the Q unsatisfied) - which means we go around again.
-			switch rqi.run() {
-			case UnRun:
-				// This case really should not need to be handled here. I am
-				// leaving this here in the event design changes such that it
-				// comes to be. Testing for it will be difficult without some
-				// sort of complication / interface on the run method. It is
-				// at least captured and handled.
-				fatalMsg := fmt.Sprintf("Failed to process task %s.", rqi.name)
+			if fatalMsg := rq.validate(); len(fatalMsg) > 0 {
 				if BehaveUnresolvIsErr {
 					return fmt.Errorf("%s", fatalMsg)
 				}
 				log.Fatalf("%s", fatalMsg)
-			case TryAgain:
-				satisfied = false
-			case Stop:
-				// This returns the ONLY error in this method. All others
-				// are asserts.
-				return ErrQStopped
 			}
 		}
 
-		passes++
+		// Collected failures (that are all that remain) end processing.
+		if satisfied && len(rq.failures) > 0 {
+			return rq.failed()
+		}
 
 		if satisfied {
+			rq.saveOrder()
 			return
 		}
+
+		// A stalled Q is abandoned early (rather than using up the budget).
+		if rq.progress == before && len(rq.q) == qlen {
+			idle++
+		} else {
+			idle = 0
+		}
+
+		if limit := rq.stallLimit(); limit > 0 && idle >= limit {
+			stalled = true
+			break
+		}
 	}
 
 	// The Q has now run as many times as there are items in the Q. Assuming a
@@ -312,17 +465,36 @@ func (rq *InitQ) process(unsatIsError bool) (err error) {
 	// Generate the error message content (even if it is not used).
 	remaining := make([]string, 0)
 	for _, rqi := range rq.q {
-		if rqi.state == TryAgain {
+		if rqi.state == TryAgain && !rq.excluded(rqi) {
 			remaining = append(remaining, rqi.name)
 		}
 	}
 
+	// With collected failures, the unresolved tasks are likely caused by
+	// them (by 'sense'). This is reported with the failures.
+	if len(rq.failures) > 0 {
+		qur := newQUnresolvable(remaining)
+		qur.diags = rq.diagnose(remaining)
+		qur.stalled = stalled
+		return rq.failed(qur)
+	}
+
 	// The explicit / priority case: The caller wants a meaningful message.
 	if unsatIsError {
-		err = newQUnresolvable(remaining)
+		qur := newQUnresolvable(remaining)
+		qur.diags = rq.diagnose(remaining)
+		qur.stalled = stalled
+		err = qur
 		return
 	}
 
+	if stalled {
+		if BehaveUnresolvIsErr == false {
+			log.Fatalf("run Q stalled (%s remain)", strings.Join(remaining, ","))
+		}
+		return ErrQStalled
+	}
+
 	// This *excludes* the testable case - with a standard / comparable error.
 	// The value is inverted (== false) so that the method ends with a return.
 	if BehaveUnresolvIsErr == false {
@@ -336,6 +508,303 @@ func (rq *InitQ) process(unsatIsError bool) (err error) {
 
 /* ======================================================================== */
 
+// panicErr returns the error (wrapping ErrQPanicked and the value) of a
+// recovered panic.
+func panicErr(r any) error {
+
+	if perr, ok := r.(error); ok {
+		return fmt.Errorf("%w: %w", ErrQPanicked, perr)
+	}
+
+	return fmt.Errorf("%w: %v", ErrQPanicked, r)
+}
+
+/* ======================================================================== */
+
+// pass runs a single pass of the Q - in series, or in parallel (when
+// enabled with SetParallel). It reports if every task was Satisfied. An
+// error ends processing. It must be called with the lock held.
+func (rq *InitQ) pass(ctx context.Context) (satisfied bool, err error) {
+
+	if rq.parallel > 1 {
+		return rq.parallelPass(ctx)
+	}
+
+	// Assume the Q has been satisfied - unless shown otherwise.
+	satisfied = true
+
+	// The range is over the Q as it was at the start of the pass (in
+	// priority / preference order), so new tasks are not run until the next
+	// pass.
+	for _, rqi := range rq.schedule() {
+
+		// Failed (or depends on a task that failed) - when collecting
+		// failures.
+		if rq.excluded(rqi) {
+			continue
+		}
+
+		// Check for dependencies. The tasks that the last call said it was
+		// waiting on are treated the same as explicit dependencies.
+		if !rq.ready(rqi) {
+			rq.block(rqi)
+			satisfied = false
+			continue
+		}
+
+		// "run" each item. If previously satisfied, the run will be skipped.
+		// We only care about the 'unsatisfied' cases (that prove the Q
+		// unsatisfied) - which means we go around again.
+		if rqi.runnable() {
+
+			// Nothing has changed since the last call.
+			if rq.stale(rqi) {
+				rq.skipped++
+				satisfied = false
+				continue
+			}
+
+			// A canceled (or timed out) context stops the Q before the next
+			// task is run.
+			if ctx.Err() != nil {
+				return false, fmt.Errorf("%w: %w", ErrQCanceled, context.Cause(ctx))
+			}
+
+			rq.emit(Event{Kind: EventTaskStart, Task: rqi.name})
+
+			t := newTask(ctx, rqi.name)
+
+			start := time.Now()
+			rqi.settle(rq.invoke(rqi, t))
+			end := time.Now()
+
+			if fatalMsg := rq.record(rqi, t, start, end); len(fatalMsg) > 0 {
+				if BehaveUnresolvIsErr {
+					return false, fmt.Errorf("%s", fatalMsg)
+				}
+				log.Fatalf("%s", fatalMsg)
+			}
+		}
+
+		switch rqi.state {
+		case UnRun:
+			// This case really should not need to be handled here. I am
+			// leaving this here in the event design changes such that it comes
+			// to be. Testing for it will be difficult without some sort of
+			// complication / interface on the run method. It is at least
+			// captured and handled.
+			fatalMsg := fmt.Sprintf("Failed to process task %s.", rqi.name)
+			if BehaveUnresolvIsErr {
+				return false, fmt.Errorf("%s", fatalMsg)
+			}
+			log.Fatalf("%s", fatalMsg)
+		case TryAgain:
+			satisfied = false
+		case Stop:
+			// This returns the ONLY error in this method. All others are
+			// asserts. (Unless failures are collected.)
+			if err = rq.fail(rqi); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return
+}
+
+/* ======================================================================== */
+
+// ready reports if the explicit dependencies of the item (and the tasks the
+// last call said it was waiting on) are Satisfied.
+func (rq *InitQ) ready(rqi *initQItem) bool {
+
+	for _, dep := range slices.Concat(rqi.deps, rqi.waits) {
+		if rq.satisfied(dep) == false {
+			return false
+		}
+	}
+
+	return true
+}
+
+/* ======================================================================== */
+
+// block records that the item was not run (in this pass) because it is not
+// ready.
+func (rq *InitQ) block(rqi *initQItem) {
+	rqi.state = TryAgain
+	rq.emit(Event{Kind: EventTaskBlocked, Task: rqi.name})
+}
+
+/* ======================================================================== */
+
+// record records a (settled) call of the task function - in the trace, the
+// order of completion, and to the observers. The return is an (assertion)
+// message if the task handle was misused.
+func (rq *InitQ) record(rqi *initQItem, t *Task, start time.Time, end time.Time) (fatalMsg string) {
+
+	rqi.await(t)
+	rqi.heard = rq.progress
+
+	rq.trace = append(rq.trace, Attempt{Task: rqi.name, Pass: rq.passes + 1, Result: rqi.state, Start: start, End: end})
+
+	if rqi.state == Satisfied {
+		rq.seq++
+		rqi.seq = rq.seq
+		rq.progress++
+		rq.saveCheckpoint()
+	}
+
+	rq.emit(Event{Kind: EventTaskEnd, Time: end, Task: rqi.name, Result: rqi.state, Elapsed: end.Sub(start)})
+
+	// The waits are checked (like dependencies) against the task labels as
+	// soon as they are known.
+	return rq.validateWaits(rqi)
+}
+
+/* ======================================================================== */
+
+// invoke calls the task function of the item. It must be called with the
+// lock held. The lock is released while the function runs (and re-acquired
+// even if the function panics).
+func (rq *InitQ) invoke(rqi *initQItem, t *Task) ReqResult {
+
+	rq.running = append(rq.running, rqi.name)
+	defer func() {
+		rq.running = slices.DeleteFunc(rq.running, func(n string) bool { return n == rqi.name })
+	}()
+
+	rq.mu.Unlock()
+	defer rq.mu.Lock()
+
+	return rqi.f(t)
+}
+
+/* ======================================================================== */
+
+// setAddErr records an Add error (under the lock). Add may be called from a
+// task function while the Q is processing.
+func (rq *InitQ) setAddErr(msg string) {
+
+	rq.mu.Lock()
+	rq.addErr = msg
+	rq.mu.Unlock()
+}
+
+/* ======================================================================== */
+
+// validate checks the task labels and dependencies of the Q. The return is
+// an (assertion) message describing the first problem found - or empty if
+// no problems were found.
+func (rq *InitQ) validate() (fatalMsg string) {
+
+	// Check to see if any dependencies are 'dangling'. This is the case
+	// where a 'semaphore' dependency references a task that does not exist.
+	// This cannot be checked in the Add calls.
+	// First build a simpler lookup list.
+	validLabels := make([]string, 0)
+	for _, task := range rq.q {
+
+		// This part *could* be done in Add - but easier here.
+		if slices.Contains(validLabels, task.name) {
+			return fmt.Sprintf("The %s task label was used more than once.", task.name)
+		}
+
+		validLabels = append(validLabels, task.name)
+	}
+	// Now walk all dependencies looking for solid matches.
+	for _, task := range rq.q {
+		for _, dep := range task.deps {
+			if !slices.Contains(validLabels, dep) {
+				return fmt.Sprintf("Task %s has dependency %s that does not match any existing task.", task.name, dep)
+			}
+		}
+	}
+
+	// Limits (for parallel processing) must be defined.
+	return rq.validateLimits()
+}
+
+/* ======================================================================== */
+
+// validateWaits checks the tasks that the item said it was waiting on. As
+// with validate, the return is an (assertion) message - or empty.
+func (rq *InitQ) validateWaits(rqi *initQItem) (fatalMsg string) {
+
+	for _, w := range rqi.waits {
+
+		if w == rqi.name {
+			return fmt.Sprintf("Task %s is waiting on itself.", rqi.name)
+		}
+
+		if rq.item(w) == nil {
+			return fmt.Sprintf("Task %s is waiting on %s that does not match any existing task.", rqi.name, w)
+		}
+	}
+
+	return
+}
+
+/* ======================================================================== */
+
+// passBudget is the maximum number of passes of the Q allowed in a process
+// run. Assuming a worst case ordering, each pass satisfies at least one
+// task - plus one pass to confirm. (Unless set with SetMaxPasses.)
+func (rq *InitQ) passBudget() int {
+
+	if rq.maxPasses > 0 {
+		return rq.maxPasses
+	}
+
+	return len(rq.q) + 1
+}
+
+/* ======================================================================== */
+
+// States returns the current state of every task in the Q, by task label.
+func (rq *InitQ) States() (states map[string]ReqResult) {
+
+	states = make(map[string]ReqResult)
+
+	if rq == nil {
+		return
+	}
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	for _, rqi := range rq.q {
+		states[rqi.name] = rqi.state
+	}
+
+	return
+}
+
+/* ======================================================================== */
+
+// completed returns the Satisfied tasks in the order they were Satisfied.
+// Unlike the trace, this spans process runs (tasks that were Satisfied in a
+// previous run remain in place).
+func (rq *InitQ) completed() (names []string) {
+
+	done := make([]*initQItem, 0)
+	for _, rqi := range rq.q {
+		if rqi.state == Satisfied {
+			done = append(done, rqi)
+		}
+	}
+
+	slices.SortFunc(done, func(a, b *initQItem) int { return a.seq - b.seq })
+
+	for _, rqi := range done {
+		names = append(names, rqi.name)
+	}
+
+	return
+}
+
+/* ======================================================================== */
+
 // satisfied reports if a named requirement has been satisfied. This is used
 // to check required dependencies of a requirement.
 func (rq *InitQ) satisfied(name string) bool {
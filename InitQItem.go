@@ -1,14 +1,18 @@
 package initq
 
-import "log"
+import (
+	"context"
+	"log"
+	"time"
+)
 
 /* ------------------------------------------------------------------------ */
 
 // initQItem contains all items necessary to define a required task, as well
 // as the optional 'semaphore' expression of requirements.
 type initQItem struct {
-	// f is the init function pointer/reference.
-	f QFunc
+	// f is the init function pointer/reference. (A QFunc is adapted.)
+	f TaskFunc
 
 	// name is the "name" of the requirement. It may be used for Fatal() error
 	// messaging or 'dependent semaphore' checks. The name is case sensitive.
@@ -22,17 +26,64 @@ type initQItem struct {
 	// before this item can attempt to run. These are used when there is no other
 	// indication of success of dependent tasks.
 	deps []string
+
+	// invocations is the count of times the task function was called.
+	invocations int
+
+	// last is the result of the last task function call. Unlike state, it
+	// is not changed when the task is blocked by its dependencies.
+	last ReqResult
+
+	// seq is the order in which the item was Satisfied (relative to other
+	// items in the Q). It is zero if not Satisfied.
+	seq int
+
+	// waits are the tasks that the last call (that returned TryAgain) said
+	// it was waiting on - and reason is why it said it could not complete.
+	waits  []string
+	reason string
+
+	// err is the error the last call (that returned Stop) gave.
+	err error
+
+	// priority and before (the tasks this task is preferred to run before)
+	// order the tasks of a pass.
+	priority int
+	before   []string
+
+	// uses are the named limits of the task, and limitWaits the time spent
+	// waiting on each (in the last process run).
+	uses       []string
+	limitWaits map[string]time.Duration
+
+	// compensation (when set) undoes the task. See SetCompensation.
+	compensation func() error
+
+	// always opts the task out of checkpoints. See AlwaysRun.
+	always bool
+
+	// heard is the (process run) progress count when the task function was
+	// last called. See ScheduleEvents.
+	heard int
 }
 
 /* ======================================================================== */
 
 // newInitQItem is the preferred constructor for new Q items.
 func newInitQItem(name string, f QFunc, deps ...string) (rqi *initQItem) {
+	return newTaskItem(name, func(*Task) ReqResult { return f() }, deps...)
+}
+
+/* ======================================================================== */
+
+// newTaskItem is newInitQItem for a TaskFunc.
+func newTaskItem(name string, f TaskFunc, deps ...string) (rqi *initQItem) {
 
 	rqi = new(initQItem)
 
 	rqi.f = f
 	rqi.name = name
+	rqi.limitWaits = make(map[string]time.Duration)
 	rqi.state = UnRun
 	for _, d := range deps {
 		rqi.deps = append(rqi.deps, d)
@@ -44,7 +95,8 @@ func newInitQItem(name string, f QFunc, deps ...string) (rqi *initQItem) {
 /* ======================================================================== */
 
 // run will run the required task function if it should be run. Once a task
-// function returns Satisfied, then it will not be run again.
+// function returns Satisfied, then it will not be run again. A task that
+// previously returned Stop is run again (when the Q is processed again).
 func (rqi *initQItem) run() ReqResult {
 
 	if rqi == nil {
@@ -52,9 +104,62 @@ func (rqi *initQItem) run() ReqResult {
 	}
 
 	// Only run if one should.
-	if rqi.state == TryAgain || rqi.state == UnRun {
-		rqi.state = rqi.f()
+	if rqi.runnable() {
+		t := newTask(context.Background(), rqi.name)
+		rqi.settle(rqi.f(t))
+		rqi.await(t)
 	}
 
 	return rqi.state
 }
+
+/* ======================================================================== */
+
+// runnable reports if the task function should be run (it has not yet been
+// Satisfied).
+func (rqi *initQItem) runnable() bool {
+	return rqi.state != Satisfied
+}
+
+/* ======================================================================== */
+
+// settle records the result of a task function invocation.
+func (rqi *initQItem) settle(result ReqResult) {
+	rqi.invocations++
+	rqi.state = result
+	rqi.last = result
+}
+
+/* ======================================================================== */
+
+// await records what the task (handle) said it was waiting on. This is
+// only kept while the task is TryAgain. (Likewise the error of a Stop.)
+func (rqi *initQItem) await(t *Task) {
+
+	rqi.err = nil
+	if rqi.state == Stop {
+		rqi.err = t.err
+	}
+
+	if rqi.state != TryAgain {
+		rqi.waits = nil
+		rqi.reason = ""
+		return
+	}
+
+	rqi.waits = t.waits
+	rqi.reason = t.reason
+}
+
+/* ======================================================================== */
+
+// reset returns the item to the initialized (UnRun) state.
+func (rqi *initQItem) reset() {
+	rqi.state = UnRun
+	rqi.invocations = 0
+	rqi.last = UnRun
+	rqi.seq = 0
+	rqi.waits = nil
+	rqi.reason = ""
+	rqi.err = nil
+}
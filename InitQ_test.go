@@ -379,3 +379,75 @@ func TestInitQ(t *testing.T) {
 	}
 
 }
+
+/* ======================================================================== */
+
+func TestDynamicAdd(t *testing.T) {
+
+	var rq *InitQ
+
+	// ----------
+	// A config task that (once run) knows how many listeners to start.
+
+	rq = NewInitQ()
+	started := 0
+
+	rq.Add("ready", func() ReqResult {
+		if started < 3 {
+			return TryAgain
+		}
+		return Satisfied
+	}, "config")
+
+	rq.Add("config", func() ReqResult {
+		for _, name := range []string{"lsn1", "lsn2", "lsn3"} {
+			rq.Add(name, func() ReqResult {
+				started++
+				return Satisfied
+			}, "config")
+		}
+		return Satisfied
+	})
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if started != 3 || len(rq.States()) != 5 {
+		t.Errorf("Expected 3 child tasks to run; got %d", started)
+	}
+
+	// ----------
+	// Child tasks are validated - as are the Add calls.
+
+	BehaveUnresolvIsErr = true
+
+	rq = NewInitQ()
+
+	rq.Add("parent", func() ReqResult {
+		rq.Add("child", func() ReqResult { return Satisfied }, "typo")
+		return Satisfied
+	})
+
+	if err := rq.Process(); err == nil {
+		t.Errorf("A Q with a dangling child dependency managed to finish.")
+	} else {
+		if !strings.Contains(err.Error(), "typo") {
+			t.Errorf("Expected a specific error - got %s", err.Error())
+		}
+	}
+
+	rq = NewInitQ()
+
+	rq.Add("parent", func() ReqResult {
+		rq.Add("child", nil)
+		return Satisfied
+	})
+
+	if err := rq.Process(); err == nil {
+		t.Errorf("A Q with a nil child function managed to finish.")
+	}
+
+	BehaveUnresolvIsErr = false
+
+}
@@ -0,0 +1,244 @@
+package initq
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+/* ------------------------------------------------------------------------ */
+
+// LearnedOrder is the order in which tasks were Satisfied in a previous
+// (successful) run of the Q.
+type LearnedOrder struct {
+	// Order is the task labels in the order they were Satisfied.
+	Order []string `json:"order"`
+
+	// Baseline is the number of task function calls of the run that was
+	// not ordered by a learned order. It is used to report savings.
+	Baseline int `json:"baseline"`
+}
+
+/* ------------------------------------------------------------------------ */
+
+// OrderStore persists a LearnedOrder between runs of an application. A
+// store that has nothing saved returns an empty LearnedOrder (and no error).
+type OrderStore interface {
+	LoadOrder() (LearnedOrder, error)
+	SaveOrder(lo LearnedOrder) error
+}
+
+/* ------------------------------------------------------------------------ */
+
+// fileOrderStore is the (JSON) file based OrderStore.
+type fileOrderStore struct {
+	path string
+}
+
+/* ======================================================================== */
+
+// FileOrderStore returns an OrderStore that saves to a JSON file. A missing
+// file is an empty LearnedOrder.
+func FileOrderStore(path string) OrderStore {
+	return &fileOrderStore{path: path}
+}
+
+/* ======================================================================== */
+
+// LoadOrder reads the order from the file.
+func (fos *fileOrderStore) LoadOrder() (lo LearnedOrder, err error) {
+
+	data, err := os.ReadFile(fos.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return lo, nil
+	}
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(data, &lo)
+	return
+}
+
+/* ======================================================================== */
+
+// SaveOrder writes the order to the file. It is written to a temporary file
+// and renamed so that a partial write does not corrupt the store.
+func (fos *fileOrderStore) SaveOrder(lo LearnedOrder) (err error) {
+
+	data, err := json.Marshal(lo)
+	if err != nil {
+		return
+	}
+
+	return replaceFile(fos.path, data)
+}
+
+/* ======================================================================== */
+
+// replaceFile writes the data to a temporary file (in the same directory)
+// and renames it to the path.
+func replaceFile(path string, data []byte) (err error) {
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return
+	}
+
+	if err = tmp.Close(); err != nil {
+		return
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+/* ======================================================================== */
+
+// LearnOrder enables the learned ordering of the Q. When processed, the
+// passes are ordered by the order that tasks were Satisfied in the last
+// successful run. A well ordered Q satisfies (nearly) every task on the
+// first pass, so TryAgain calls are avoided. The Q itself (as reported by
+// Status) keeps the order the tasks were added.
+//
+// Tasks that are not in the learned order (new tasks) are placed after the
+// learned tasks, in the order they were added. Tasks in the learned order
+// that no longer exist are ignored. When the set of tasks changes, the
+// savings baseline is reset.
+//
+// Store errors do not cause processing to fail. The last error is available
+// from OrderErr. A failed load falls back to the order the tasks were added.
+func (rq *InitQ) LearnOrder(store OrderStore) {
+
+	rq.mu.Lock()
+	rq.orderStore = store
+	rq.mu.Unlock()
+}
+
+/* ======================================================================== */
+
+// InvocationsSaved returns the number of task function calls saved (in the
+// last run) by the learned order - compared with the baseline run.
+func (rq *InitQ) InvocationsSaved() int {
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	return rq.saved
+}
+
+/* ======================================================================== */
+
+// OrderErr returns the last error from the OrderStore (or nil).
+func (rq *InitQ) OrderErr() error {
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	return rq.orderErr
+}
+
+/* ======================================================================== */
+
+// applyOrder loads the learned order (and ranks the tasks by it) for the
+// process run. The Q itself is not re-ordered. It must be called with the
+// lock held.
+func (rq *InitQ) applyOrder() {
+
+	rq.learned = LearnedOrder{}
+	rq.rank = nil
+	rq.saved = 0
+
+	if rq.orderStore == nil {
+		return
+	}
+
+	lo, err := rq.orderStore.LoadOrder()
+	if err != nil {
+		rq.orderErr = err
+		return
+	}
+
+	rq.learned = lo
+
+	rq.rank = make(map[string]int)
+	for i, name := range lo.Order {
+		rq.rank[name] = i
+	}
+}
+
+/* ======================================================================== */
+
+// ranked returns the Q in the learned order. Tasks that are not in the
+// learned order (new tasks) follow, in the order they were added. It must be
+// called with the lock held.
+func (rq *InitQ) ranked() (order []*initQItem) {
+
+	order = slices.Clone(rq.q)
+
+	// Stable, so new tasks keep the order they were added.
+	slices.SortStableFunc(order, func(a, b *initQItem) int {
+		ai, aok := rq.rank[a.name]
+		bi, bok := rq.rank[b.name]
+		switch {
+		case !aok && !bok:
+			return 0
+		case !aok:
+			return 1
+		case !bok:
+			return -1
+		}
+		return ai - bi
+	})
+
+	return
+}
+
+/* ======================================================================== */
+
+// saveOrder saves the order of the (successful) run, and computes the
+// savings. It must be called with the lock held.
+func (rq *InitQ) saveOrder() {
+
+	// Nothing ran (the Q was already complete). There is nothing to learn.
+	if rq.orderStore == nil || len(rq.trace) == 0 {
+		return
+	}
+
+	lo := LearnedOrder{Order: rq.completed(), Baseline: rq.learned.Baseline}
+
+	// The first run (or a run with a different set of tasks) is the
+	// baseline.
+	sameTasks := maps.Equal(setOf(lo.Order), setOf(rq.learned.Order))
+	if len(rq.learned.Order) == 0 || !sameTasks {
+		lo.Baseline = len(rq.trace)
+	}
+
+	rq.saved = lo.Baseline - len(rq.trace)
+
+	if err := rq.orderStore.SaveOrder(lo); err != nil {
+		rq.orderErr = err
+	}
+}
+
+/* ======================================================================== */
+
+// setOf returns the names as a set.
+func setOf(names []string) (set map[string]bool) {
+
+	set = make(map[string]bool)
+	for _, n := range names {
+		set[n] = true
+	}
+
+	return
+}
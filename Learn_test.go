@@ -0,0 +1,129 @@
+package initq
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+/* ======================================================================== */
+
+// failStore is an OrderStore that always fails.
+type failStore struct{}
+
+func (failStore) LoadOrder() (LearnedOrder, error) { return LearnedOrder{}, errors.New("load failed") }
+func (failStore) SaveOrder(LearnedOrder) error     { return errors.New("save failed") }
+
+/* ======================================================================== */
+
+func TestLearnOrder(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "order.json")
+	store := FileOrderStore(path)
+
+	// build creates a worst case (backwards) ordered Q of sense-style tasks.
+	build := func(extra bool) *InitQ {
+
+		rq := NewInitQ()
+		cd := new(coredata)
+
+		rq.Add("dbconn", cd.SetupDBConnection)
+		rq.Add("config", cd.ReadConfigFile)
+		rq.Add("cmdline", cd.ParseCommandLIne)
+		if extra {
+			rq.Add("extra", func() ReqResult { return Satisfied })
+		}
+
+		rq.LearnOrder(store)
+		return rq
+	}
+
+	// ----------
+	// The first run is the baseline (3 + 2 + 1 invocations).
+
+	rq := build(false)
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if rq.Invocations() != 6 || rq.InvocationsSaved() != 0 {
+		t.Errorf("Expected 6 invocations / 0 saved; got %d / %d", rq.Invocations(), rq.InvocationsSaved())
+	}
+
+	lo, err := store.LoadOrder()
+	if err != nil || !slices.Equal(lo.Order, []string{"cmdline", "config", "dbconn"}) || lo.Baseline != 6 {
+		t.Errorf("Unexpected stored order %+v (%v)", lo, err)
+	}
+
+	// ----------
+	// The next start uses the learned order.
+
+	rq = build(false)
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if rq.Passes() != 1 || rq.Invocations() != 3 || rq.InvocationsSaved() != 3 {
+		t.Errorf("Expected 1 pass / 3 invocations / 3 saved; got %d / %d / %d", rq.Passes(), rq.Invocations(), rq.InvocationsSaved())
+	}
+
+	// The Q itself keeps the order the tasks were added.
+	var names []string
+	for _, ts := range rq.Status() {
+		names = append(names, ts.Name)
+	}
+
+	if !slices.Equal(names, []string{"dbconn", "config", "cmdline"}) {
+		t.Errorf("Expected the added order from Status; got %v", names)
+	}
+
+	// ----------
+	// A new task - the order is used, the baseline is reset.
+
+	rq = build(true)
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if rq.Invocations() != 4 || rq.InvocationsSaved() != 0 {
+		t.Errorf("Expected 4 invocations / 0 saved; got %d / %d", rq.Invocations(), rq.InvocationsSaved())
+	}
+
+	// ----------
+	// A corrupt store falls back to the added order.
+
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatalf("Unable to write the store - %s", err.Error())
+	}
+
+	rq = build(false)
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if rq.OrderErr() == nil || rq.Invocations() != 6 {
+		t.Errorf("Expected a store error and 6 invocations; got %v / %d", rq.OrderErr(), rq.Invocations())
+	}
+
+	// ----------
+	// A store that always fails does not fail the Q.
+
+	rq = NewInitQ()
+	rq.Add("one", func() ReqResult { return Satisfied })
+	rq.LearnOrder(failStore{})
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if rq.OrderErr() == nil {
+		t.Errorf("Expected a store error")
+	}
+
+}
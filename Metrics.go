@@ -0,0 +1,220 @@
+package initq
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+/* ------------------------------------------------------------------------ */
+
+// DefaultBuckets are the (seconds) histogram buckets used by NewMetrics.
+var DefaultBuckets = []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60}
+
+/* ------------------------------------------------------------------------ */
+
+// histogram is a (Prometheus style) cumulative histogram.
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+/* ------------------------------------------------------------------------ */
+
+// attemptKey is the label set of the attempt counter.
+type attemptKey struct {
+	task   string
+	result ReqResult
+}
+
+/* ------------------------------------------------------------------------ */
+
+// Metrics collects metrics from a processing Q (it is an Observer) and
+// exposes them in the Prometheus text exposition format. It is an
+// http.Handler (for the scrape endpoint). The metrics are:
+//
+//	initq_task_duration_seconds     histogram of task function call time
+//	initq_task_attempts_total       task function calls (by task and result)
+//	initq_passes_total              passes of the Q
+//	initq_unsatisfied_tasks         tasks not (yet) Satisfied
+//	initq_process_runs_total        process runs (by outcome)
+//
+// The outcome is one of satisfied, stopped, canceled, panicked or
+// unresolved.
+//
+// A single Metrics may observe many Q runs (or many Qs).
+type Metrics struct {
+	// buckets are the histogram upper bounds (seconds).
+	buckets []float64
+
+	// mu protects all below.
+	mu sync.Mutex
+
+	durations   map[string]*histogram
+	attempts    map[attemptKey]uint64
+	passes      uint64
+	unsatisfied int
+	runs        map[string]uint64
+}
+
+/* ======================================================================== */
+
+// NewMetrics creates a Metrics collector with the DefaultBuckets.
+//
+//	m := initq.NewMetrics()
+//	iq.Observe(m.Observe)
+//	http.Handle("/metrics", m)
+func NewMetrics() *Metrics {
+	return NewMetricsBuckets(DefaultBuckets)
+}
+
+/* ======================================================================== */
+
+// NewMetricsBuckets creates a Metrics collector with custom histogram
+// buckets (upper bounds, in seconds).
+func NewMetricsBuckets(buckets []float64) (m *Metrics) {
+
+	m = new(Metrics)
+	m.buckets = slices.Sorted(slices.Values(buckets))
+	m.durations = make(map[string]*histogram)
+	m.attempts = make(map[attemptKey]uint64)
+	m.runs = make(map[string]uint64)
+
+	return
+}
+
+/* ======================================================================== */
+
+// Observe is the Observer that collects the metrics.
+func (m *Metrics) Observe(ev Event) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.unsatisfied = ev.Total - ev.Satisfied
+
+	switch ev.Kind {
+	case EventTaskEnd:
+		h, ok := m.durations[ev.Task]
+		if !ok {
+			h = &histogram{counts: make([]uint64, len(m.buckets))}
+			m.durations[ev.Task] = h
+		}
+
+		secs := ev.Elapsed.Seconds()
+		for i, b := range m.buckets {
+			if secs <= b {
+				h.counts[i]++
+			}
+		}
+		h.count++
+		h.sum += secs
+
+		m.attempts[attemptKey{task: ev.Task, result: ev.Result}]++
+
+	case EventPassEnd:
+		m.passes++
+
+	case EventProcessEnd:
+		m.runs[outcome(ev.Err)]++
+	}
+}
+
+/* ======================================================================== */
+
+// outcome is the (label) outcome of a process run.
+func outcome(err error) string {
+
+	switch {
+	case err == nil:
+		return "satisfied"
+	case errors.Is(err, ErrQStopped):
+		return "stopped"
+	case errors.Is(err, ErrQCanceled):
+		return "canceled"
+	case errors.Is(err, ErrQPanicked):
+		return "panicked"
+	}
+
+	return "unresolved"
+}
+
+/* ======================================================================== */
+
+// ServeHTTP implements http.Handler (the scrape endpoint).
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	// There is no recourse for a failed write.
+	_, _ = m.WriteTo(w)
+}
+
+/* ======================================================================== */
+
+// WriteTo writes the metrics in the Prometheus text exposition format. It
+// implements io.WriterTo.
+func (m *Metrics) WriteTo(w io.Writer) (n int64, err error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var sb strings.Builder
+
+	tasks := slices.Sorted(maps.Keys(m.durations))
+
+	sb.WriteString("# HELP initq_task_duration_seconds Time spent in task function calls.\n")
+	sb.WriteString("# TYPE initq_task_duration_seconds histogram\n")
+	for _, t := range tasks {
+		h := m.durations[t]
+		for i, b := range m.buckets {
+			fmt.Fprintf(&sb, "initq_task_duration_seconds_bucket{task=\"%s\",le=\"%g\"} %d\n", escapeLabel(t), b, h.counts[i])
+		}
+		fmt.Fprintf(&sb, "initq_task_duration_seconds_bucket{task=\"%s\",le=\"+Inf\"} %d\n", escapeLabel(t), h.count)
+		fmt.Fprintf(&sb, "initq_task_duration_seconds_sum{task=\"%s\"} %g\n", escapeLabel(t), h.sum)
+		fmt.Fprintf(&sb, "initq_task_duration_seconds_count{task=\"%s\"} %d\n", escapeLabel(t), h.count)
+	}
+
+	keys := slices.SortedFunc(maps.Keys(m.attempts), func(a, b attemptKey) int {
+		if c := strings.Compare(a.task, b.task); c != 0 {
+			return c
+		}
+		return int(a.result) - int(b.result)
+	})
+
+	sb.WriteString("# HELP initq_task_attempts_total Task function calls by result.\n")
+	sb.WriteString("# TYPE initq_task_attempts_total counter\n")
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "initq_task_attempts_total{task=\"%s\",result=\"%s\"} %d\n", escapeLabel(k.task), k.result, m.attempts[k])
+	}
+
+	sb.WriteString("# HELP initq_passes_total Passes of the Q.\n")
+	sb.WriteString("# TYPE initq_passes_total counter\n")
+	fmt.Fprintf(&sb, "initq_passes_total %d\n", m.passes)
+
+	sb.WriteString("# HELP initq_unsatisfied_tasks Tasks that are not Satisfied.\n")
+	sb.WriteString("# TYPE initq_unsatisfied_tasks gauge\n")
+	fmt.Fprintf(&sb, "initq_unsatisfied_tasks %d\n", m.unsatisfied)
+
+	sb.WriteString("# HELP initq_process_runs_total Process runs by outcome.\n")
+	sb.WriteString("# TYPE initq_process_runs_total counter\n")
+	for _, o := range slices.Sorted(maps.Keys(m.runs)) {
+		fmt.Fprintf(&sb, "initq_process_runs_total{outcome=\"%s\"} %d\n", o, m.runs[o])
+	}
+
+	c, err := io.WriteString(w, sb.String())
+	return int64(c), err
+}
+
+/* ======================================================================== */
+
+// escapeLabel escapes a Prometheus label value.
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
@@ -0,0 +1,93 @@
+package initq
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+/* ======================================================================== */
+
+func TestMetrics(t *testing.T) {
+
+	m := NewMetricsBuckets([]float64{10, 0.5})
+
+	// ----------
+	// A successful run (config tries again once).
+
+	rq := NewInitQ()
+	rq.Observe(m.Observe)
+
+	cd := new(coredata)
+	rq.Add("config", cd.ReadConfigFile)
+	rq.Add("cmdline", cd.ParseCommandLIne)
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	// ----------
+	// A stopped run (on the "db" step).
+
+	rq = NewInitQ()
+	rq.Observe(m.Observe)
+
+	rq.Add("cmdline", func() ReqResult { return Satisfied })
+	rq.Add("db", func() ReqResult { return Stop })
+
+	_ = rq.Process()
+
+	// ----------
+	// A panicked run (the panic is re-raised).
+
+	rq = NewInitQ()
+	rq.Observe(m.Observe)
+
+	rq.Add("panicker", func() ReqResult { panic("boom") })
+
+	func() {
+		defer func() { _ = recover() }()
+		_ = rq.Process()
+	}()
+
+	// ----------
+	// Scrape.
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("Unexpected content type %s", rec.Header().Get("Content-Type"))
+	}
+
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		`initq_task_duration_seconds_bucket{task="config",le="0.5"} 2`,
+		`initq_task_duration_seconds_bucket{task="config",le="10"} 2`,
+		`initq_task_duration_seconds_bucket{task="config",le="+Inf"} 2`,
+		`initq_task_duration_seconds_count{task="cmdline"} 2`,
+		`initq_task_attempts_total{task="config",result="tryagain"} 1`,
+		`initq_task_attempts_total{task="config",result="satisfied"} 1`,
+		`initq_task_attempts_total{task="db",result="stop"} 1`,
+		`initq_passes_total 2`,
+		`initq_unsatisfied_tasks 1`,
+		`initq_process_runs_total{outcome="satisfied"} 1`,
+		`initq_process_runs_total{outcome="stopped"} 1`,
+		`initq_process_runs_total{outcome="panicked"} 1`,
+		"# TYPE initq_task_duration_seconds histogram",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Missing %q in:\n%s", want, body)
+		}
+	}
+
+	// ----------
+	// Label escaping.
+
+	if escapeLabel("a\"b\\c\nd") != `a\"b\\c\nd` {
+		t.Errorf("Unexpected escaping %s", escapeLabel("a\"b\\c\nd"))
+	}
+
+}
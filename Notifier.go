@@ -0,0 +1,224 @@
+package initq
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+/* ------------------------------------------------------------------------ */
+
+// Notifier implements the systemd sd_notify protocol for a Q. It is used by
+// services with Type=notify. As an Observer of the Q it sends:
+//
+//   - STATUS= as each task is started (e.g. "initializing: db (3/12)").
+//   - EXTEND_TIMEOUT_USEC= periodically while (long) tasks run. This is
+//     only sent when enabled with SetExtendTimeout. With parallel
+//     processing it is sent until the last running task ends.
+//   - READY=1 when the Q is processed successfully.
+//   - STATUS= with the error when processing fails.
+//
+// STOPPING=1 is sent by calling Stopping - such as during tear down of the
+// application.
+//
+// The messages are written to the unixgram socket named in $NOTIFY_SOCKET.
+// When the variable is not set (not running under systemd) the Notifier does
+// nothing.
+type Notifier struct {
+	// addr is the notify socket address (empty if disabled).
+	addr string
+
+	// extend is the EXTEND_TIMEOUT_USEC value. Zero disables it.
+	extend time.Duration
+
+	// mu protects the fields below (and serializes writes).
+	mu sync.Mutex
+
+	// running is the number of tasks running, and stopExtend stops the
+	// extend goroutine (that runs while any task does).
+	running    int
+	stopExtend chan struct{}
+
+	// err is the first error sending a message.
+	err error
+}
+
+/* ======================================================================== */
+
+// NewNotifier creates a Notifier for the socket named in $NOTIFY_SOCKET.
+//
+// Use the Observe method of the Notifier as an Observer of the Q:
+//
+//	n := initq.NewNotifier()
+//	iq.Observe(n.Observe)
+func NewNotifier() (n *Notifier) {
+
+	n = new(Notifier)
+	n.addr = os.Getenv("NOTIFY_SOCKET")
+
+	return
+}
+
+/* ======================================================================== */
+
+// Enabled reports if the Notifier has a socket to write to.
+func (n *Notifier) Enabled() bool {
+	return len(n.addr) > 0
+}
+
+/* ======================================================================== */
+
+// SetExtendTimeout enables EXTEND_TIMEOUT_USEC messages. While any task
+// runs, the start timeout is extended (by d) every d/2. A value of zero (or
+// less) disables it, and a value under a millisecond is raised to one.
+func (n *Notifier) SetExtendTimeout(d time.Duration) {
+
+	if d > 0 && d < time.Millisecond {
+		d = time.Millisecond
+	}
+
+	n.extend = d
+}
+
+/* ======================================================================== */
+
+// Notify sends a (raw) sd_notify message, such as "WATCHDOG=1". It does
+// nothing (and returns nil) when the Notifier is not enabled.
+func (n *Notifier) Notify(msg string) (err error) {
+
+	if !n.Enabled() {
+		return nil
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// A leading @ is an abstract socket (this is handled by net).
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: n.addr, Net: "unixgram"})
+	if err == nil {
+		_, err = conn.Write([]byte(msg))
+		conn.Close()
+	}
+
+	if err != nil && n.err == nil {
+		n.err = err
+	}
+
+	return
+}
+
+/* ======================================================================== */
+
+// Stopping sends STOPPING=1. It is called when the application begins to
+// tear down.
+func (n *Notifier) Stopping() error {
+	return n.Notify("STOPPING=1\nSTATUS=stopping")
+}
+
+/* ======================================================================== */
+
+// Err returns the first error sending a message (from any source).
+func (n *Notifier) Err() error {
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.err
+}
+
+/* ======================================================================== */
+
+// Observe is the Observer that drives the notifications. Send errors are not
+// returned (see Err).
+func (n *Notifier) Observe(ev Event) {
+
+	switch ev.Kind {
+	case EventProcessStart:
+		n.Notify(fmt.Sprintf("STATUS=initializing (%d/%d)", ev.Satisfied, ev.Total))
+	case EventTaskStart:
+		n.Notify(fmt.Sprintf("STATUS=initializing: %s (%d/%d)", ev.Task, ev.Satisfied+1, ev.Total))
+		n.startExtend()
+	case EventTaskEnd:
+		n.taskEnded()
+	case EventProcessEnd:
+		n.endExtend()
+		if ev.Err == nil {
+			n.Notify("READY=1\nSTATUS=ready")
+		} else {
+			n.Notify(fmt.Sprintf("STATUS=initialization failed: %s", ev.Err.Error()))
+		}
+	}
+}
+
+/* ======================================================================== */
+
+// startExtend counts a task as running, and (for the first running task)
+// starts a goroutine that extends the timeout while any task runs.
+func (n *Notifier) startExtend() {
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.running++
+
+	if n.extend <= 0 || !n.Enabled() || n.stopExtend != nil {
+		return
+	}
+
+	stop := make(chan struct{})
+	n.stopExtend = stop
+
+	msg := fmt.Sprintf("EXTEND_TIMEOUT_USEC=%d", n.extend.Microseconds())
+
+	go func() {
+		ticker := time.NewTicker(n.extend / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				n.Notify(msg)
+			}
+		}
+	}()
+}
+
+/* ======================================================================== */
+
+// taskEnded counts a task as no longer running. The extend goroutine is
+// stopped when no tasks are running.
+func (n *Notifier) taskEnded() {
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.running > 0 {
+		n.running--
+	}
+
+	if n.running == 0 && n.stopExtend != nil {
+		close(n.stopExtend)
+		n.stopExtend = nil
+	}
+}
+
+/* ======================================================================== */
+
+// endExtend stops the extend goroutine (if running) at the end of
+// processing. No tasks are running (a task that panicked has no end event).
+func (n *Notifier) endExtend() {
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.running = 0
+
+	if n.stopExtend != nil {
+		close(n.stopExtend)
+		n.stopExtend = nil
+	}
+}
@@ -0,0 +1,206 @@
+package initq
+
+import (
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+/* ======================================================================== */
+
+func TestNotifier(t *testing.T) {
+
+	// ----------
+	// Without $NOTIFY_SOCKET the Notifier does nothing.
+
+	t.Setenv("NOTIFY_SOCKET", "")
+
+	n := NewNotifier()
+	if n.Enabled() || n.Notify("READY=1") != nil {
+		t.Errorf("Expected a disabled Notifier")
+	}
+
+	// ----------
+	// A local listener stands in for systemd.
+
+	sock := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: sock, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("Unable to listen - %s", err.Error())
+	}
+	defer conn.Close()
+
+	t.Setenv("NOTIFY_SOCKET", sock)
+
+	// messages collects everything sent to the socket.
+	messages := make(chan string, 100)
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			c, err := conn.Read(buf)
+			if err != nil {
+				close(messages)
+				return
+			}
+			messages <- string(buf[:c])
+		}
+	}()
+
+	n = NewNotifier()
+	n.SetExtendTimeout(20 * time.Millisecond)
+
+	rq := NewInitQ()
+	rq.Observe(n.Observe)
+
+	rq.Add("cmdline", func() ReqResult { return Satisfied })
+	rq.Add("slow", func() ReqResult {
+		time.Sleep(50 * time.Millisecond)
+		return Satisfied
+	}, "cmdline")
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if err := n.Stopping(); err != nil {
+		t.Errorf("Unexpected Stopping error - %s", err.Error())
+	}
+
+	// Collect until STOPPING (the last message).
+	got := make([]string, 0)
+	for msg := range messages {
+		got = append(got, msg)
+		if strings.HasPrefix(msg, "STOPPING=1") {
+			break
+		}
+	}
+
+	all := strings.Join(got, "|")
+
+	for _, want := range []string{
+		"STATUS=initializing: cmdline (1/2)",
+		"STATUS=initializing: slow (2/2)",
+		"EXTEND_TIMEOUT_USEC=20000",
+		"READY=1",
+		"STOPPING=1",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("Missing %q in %q", want, all)
+		}
+	}
+
+	if n.Err() != nil {
+		t.Errorf("Unexpected send error - %s", n.Err().Error())
+	}
+
+	// ----------
+	// A failed Q reports (rather than READY).
+
+	rq = NewInitQ()
+	rq.Observe(n.Observe)
+	rq.Add("stopper", func() ReqResult { return Stop })
+	_ = rq.Process()
+
+	for msg := range messages {
+		if strings.HasPrefix(msg, "STATUS=initialization failed") {
+			break
+		}
+		if strings.Contains(msg, "READY=1") {
+			t.Errorf("Unexpected READY on a failed Q")
+		}
+	}
+
+	// ----------
+	// A panicked Q reports the panic (rather than READY).
+
+	rq = NewInitQ()
+	rq.Observe(n.Observe)
+	rq.Add("panicker", func() ReqResult { panic("boom") })
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Expected the panic to be re-raised")
+			}
+		}()
+		_ = rq.Process()
+	}()
+
+	for msg := range messages {
+		if strings.HasPrefix(msg, "STATUS=initialization failed") {
+			if !strings.Contains(msg, "boom") {
+				t.Errorf("Expected the panic value in %q", msg)
+			}
+			break
+		}
+		if strings.Contains(msg, "READY=1") {
+			t.Errorf("Unexpected READY on a panicked Q")
+		}
+	}
+
+	// ----------
+	// In parallel, the timeout is extended while any task runs - a short
+	// task (that starts and ends) does not stop it for a long one.
+
+	rq = NewInitQ()
+	rq.SetParallel(2)
+	rq.Observe(n.Observe)
+
+	rq.Add("long", func() ReqResult {
+		time.Sleep(100 * time.Millisecond)
+		return Satisfied
+	})
+	rq.Add("short", func() ReqResult { return Satisfied })
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	extends := 0
+	for msg := range messages {
+		if strings.HasPrefix(msg, "EXTEND_TIMEOUT_USEC") {
+			extends++
+		}
+		if strings.HasPrefix(msg, "READY=1") {
+			break
+		}
+	}
+
+	if extends < 3 {
+		t.Errorf("Expected the timeout to be extended while long runs; got %d", extends)
+	}
+
+	// ----------
+	// A tiny extend timeout is raised (rather than panic the ticker).
+
+	n = NewNotifier()
+	n.SetExtendTimeout(1)
+
+	rq = NewInitQ()
+	rq.Observe(n.Observe)
+	rq.Add("brief", func() ReqResult {
+		time.Sleep(5 * time.Millisecond)
+		return Satisfied
+	})
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	extended := false
+	for msg := range messages {
+		if msg == "EXTEND_TIMEOUT_USEC=1000" {
+			extended = true
+		}
+		if strings.HasPrefix(msg, "READY=1") {
+			break
+		}
+	}
+
+	if !extended {
+		t.Errorf("Expected the timeout to be extended by a millisecond")
+	}
+
+}
@@ -0,0 +1,335 @@
+package initq
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"time"
+)
+
+/*
+	Parallel processing:
+
+	- SetParallel enables running up to n task functions at once. Task
+	  functions must then be safe to run concurrently with each other.
+	- A pass launches every ready task (in priority / preference order) as
+	  slots allow. A task whose dependencies are running waits for them
+	  (within the pass) rather than being blocked until the next pass.
+	- Named limits (SetLimit) restrict the number of running tasks that use
+	  them (Uses). A task that is ready, but held back by a limit, has the
+	  time it waited recorded against that limit (LimitWaits).
+	- A Stop (or a canceled context) launches nothing further - unless
+	  failures are collected (SetCollectFailures). The tasks that
+	  are running are allowed to finish before processing returns. A panic
+	  in a task function is re-raised (once the others have finished).
+*/
+
+/* ------------------------------------------------------------------------ */
+
+// completion is the result of a task function run in parallel.
+type completion struct {
+	rqi    *initQItem
+	t      *Task
+	result ReqResult
+	start  time.Time
+	end    time.Time
+
+	// panicked (and the recovered value) when the task function panicked.
+	panicked  bool
+	recovered any
+}
+
+/* ======================================================================== */
+
+// SetParallel sets the number of task functions that may run at once. A
+// value of zero or one (the default) runs them one at a time.
+//
+// It must not be called while the Q is processing.
+func (rq *InitQ) SetParallel(n int) {
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	rq.parallel = n
+}
+
+/* ======================================================================== */
+
+// SetLimit defines a named limit - the number of tasks that use it (see
+// Uses) that may run at once. For example: "db" at 2, or "cpu" at
+// runtime.GOMAXPROCS(0). Limits only apply to parallel processing.
+//
+// It must not be called while the Q is processing.
+func (rq *InitQ) SetLimit(limit string, n int) {
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	if rq.limits == nil {
+		rq.limits = make(map[string]int)
+	}
+
+	rq.limits[limit] = n
+}
+
+/* ======================================================================== */
+
+// Uses declares the named limits that the named task is subject to. This is
+// typically called right after the task is added. The limits must be defined
+// (with SetLimit) before the Q is processed.
+//
+// An error (ErrQNoTask) is returned if the name does not match a task.
+func (rq *InitQ) Uses(name string, limits ...string) (err error) {
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	rqi := rq.item(name)
+	if rqi == nil {
+		return fmt.Errorf("%w: %s", ErrQNoTask, name)
+	}
+
+	for _, l := range limits {
+		if !slices.Contains(rqi.uses, l) {
+			rqi.uses = append(rqi.uses, l)
+		}
+	}
+
+	return
+}
+
+/* ======================================================================== */
+
+// LimitWaits returns the time the named task spent (ready, but) waiting on
+// each of its limits in the last process run.
+//
+// An error (ErrQNoTask) is returned if the name does not match a task.
+func (rq *InitQ) LimitWaits(name string) (waits map[string]time.Duration, err error) {
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	rqi := rq.item(name)
+	if rqi == nil {
+		return nil, fmt.Errorf("%w: %s", ErrQNoTask, name)
+	}
+
+	waits = make(map[string]time.Duration)
+	maps.Copy(waits, rqi.limitWaits)
+
+	return
+}
+
+/* ======================================================================== */
+
+// validateLimits checks that the limits used by tasks are defined (and can
+// be acquired). As with validate, the return is an (assertion) message - or
+// empty.
+func (rq *InitQ) validateLimits() (fatalMsg string) {
+
+	for _, rqi := range rq.q {
+		for _, l := range rqi.uses {
+
+			n, ok := rq.limits[l]
+			if !ok {
+				return fmt.Sprintf("Task %s uses limit %s that is not defined.", rqi.name, l)
+			}
+
+			if n < 1 {
+				return fmt.Sprintf("The %s limit must allow at least one task.", l)
+			}
+		}
+	}
+
+	return
+}
+
+/* ======================================================================== */
+
+// full returns the first limit of the item that is (currently) used up - or
+// empty if the item may run.
+func (rq *InitQ) full(rqi *initQItem) string {
+
+	for _, l := range rqi.uses {
+		if rq.inUse[l] >= rq.limits[l] {
+			return l
+		}
+	}
+
+	return ""
+}
+
+/* ======================================================================== */
+
+// parallelPass is a pass of the Q with task functions run in parallel. It
+// must be called with the lock held. (The lock is released while waiting on
+// running tasks.)
+func (rq *InitQ) parallelPass(ctx context.Context) (satisfied bool, err error) {
+
+	satisfied = true
+
+	pending := slices.Clone(rq.schedule())
+	done := make(chan completion, len(pending))
+	running := 0
+
+	// Where (and since when) ready tasks are held back by a limit.
+	heldOn := make(map[*initQItem]string)
+	heldAt := make(map[*initQItem]time.Time)
+
+	// stopping is set when nothing further is to be launched.
+	stopping := false
+	var panicked *completion
+
+	if rq.inUse == nil {
+		rq.inUse = make(map[string]int)
+	}
+
+	for {
+
+		// Launch what can be launched.
+		for i := 0; !stopping && i < len(pending) && running < rq.parallel; {
+
+			rqi := pending[i]
+
+			if !rqi.runnable() || rq.excluded(rqi) {
+				pending = slices.Delete(pending, i, i+1)
+				continue
+			}
+
+			// Not ready (yet). This may change as running tasks finish.
+			if !rq.ready(rqi) {
+				i++
+				continue
+			}
+
+			if rq.stale(rqi) {
+				rq.skipped++
+				satisfied = false
+				pending = slices.Delete(pending, i, i+1)
+				continue
+			}
+
+			if l := rq.full(rqi); len(l) > 0 {
+				if _, ok := heldOn[rqi]; !ok {
+					heldOn[rqi] = l
+					heldAt[rqi] = time.Now()
+				}
+				i++
+				continue
+			}
+
+			if ctx.Err() != nil {
+				err = fmt.Errorf("%w: %w", ErrQCanceled, context.Cause(ctx))
+				stopping = true
+				break
+			}
+
+			if l, ok := heldOn[rqi]; ok {
+				rqi.limitWaits[l] += time.Since(heldAt[rqi])
+			}
+
+			for _, l := range rqi.uses {
+				rq.inUse[l]++
+			}
+
+			pending = slices.Delete(pending, i, i+1)
+			running++
+
+			rq.launch(ctx, rqi, done)
+		}
+
+		if running == 0 {
+			break
+		}
+
+		// Wait (without the lock) for a task to finish.
+		rq.mu.Unlock()
+		c := <-done
+		rq.mu.Lock()
+
+		running--
+		for _, l := range c.rqi.uses {
+			rq.inUse[l]--
+		}
+		rq.running = slices.DeleteFunc(rq.running, func(n string) bool { return n == c.rqi.name })
+
+		if c.panicked {
+			if panicked == nil {
+				panicked = &c
+			}
+			stopping = true
+			continue
+		}
+
+		c.rqi.settle(c.result)
+
+		if fatalMsg := rq.record(c.rqi, c.t, c.start, c.end); len(fatalMsg) > 0 {
+			if !BehaveUnresolvIsErr {
+				log.Fatalf("%s", fatalMsg)
+			}
+			if err == nil {
+				err = fmt.Errorf("%s", fatalMsg)
+			}
+			stopping = true
+		}
+
+		switch c.rqi.state {
+		case TryAgain:
+			satisfied = false
+		case Stop:
+			if ferr := rq.fail(c.rqi); ferr != nil {
+				if err == nil {
+					err = ferr
+				}
+				stopping = true
+			}
+		}
+	}
+
+	if panicked != nil {
+		panic(panicked.recovered)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	// With nothing running, the remaining tasks are not ready.
+	for _, rqi := range pending {
+		if rq.excluded(rqi) {
+			continue
+		}
+		rq.block(rqi)
+		satisfied = false
+	}
+
+	return
+}
+
+/* ======================================================================== */
+
+// launch starts the task function of the item in a goroutine. The result is
+// sent on done. It must be called with the lock held.
+func (rq *InitQ) launch(ctx context.Context, rqi *initQItem, done chan<- completion) {
+
+	rq.running = append(rq.running, rqi.name)
+	rq.emit(Event{Kind: EventTaskStart, Task: rqi.name})
+
+	c := completion{rqi: rqi, t: newTask(ctx, rqi.name), start: time.Now()}
+
+	go func() {
+
+		defer func() {
+			if r := recover(); r != nil {
+				c.panicked = true
+				c.recovered = r
+			}
+			c.end = time.Now()
+			done <- c
+		}()
+
+		c.result = rqi.f(c.t)
+	}()
+}
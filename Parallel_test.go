@@ -0,0 +1,197 @@
+package initq
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+/* ======================================================================== */
+
+func TestParallel(t *testing.T) {
+
+	var rq *InitQ
+
+	// sleeper returns a task function that sleeps, and tracks the (maximum)
+	// number of sleepers at once.
+	var now, most atomic.Int32
+	sleeper := func(d time.Duration) QFunc {
+		return func() ReqResult {
+			n := now.Add(1)
+			for {
+				m := most.Load()
+				if n <= m || most.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(d)
+			now.Add(-1)
+			return Satisfied
+		}
+	}
+
+	// ----------
+	// Independent tasks run at once. A dependant waits (within the pass) for
+	// its running dependency.
+
+	rq = NewInitQ()
+	rq.SetParallel(4)
+
+	for i := range 4 {
+		rq.Add(fmt.Sprintf("task%d", i), sleeper(50*time.Millisecond))
+	}
+	rq.Add("after", sleeper(0), "task3")
+
+	start := time.Now()
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("Expected the tasks to run in parallel; took %s", elapsed)
+	}
+
+	if rq.Passes() != 1 || most.Load() != 4 {
+		t.Errorf("Expected 1 pass of 4 parallel tasks; got %d / %d", rq.Passes(), most.Load())
+	}
+
+	// ----------
+	// Limits.
+
+	rq = NewInitQ()
+	rq.SetParallel(10)
+	rq.SetLimit("db", 2)
+	most.Store(0)
+
+	for i := range 6 {
+		name := fmt.Sprintf("db%d", i)
+		rq.Add(name, sleeper(20*time.Millisecond))
+		if err := rq.Uses(name, "db"); err != nil {
+			t.Errorf("Unexpected error %s", err.Error())
+		}
+	}
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if most.Load() != 2 {
+		t.Errorf("Expected at most 2 db tasks at once; got %d", most.Load())
+	}
+
+	waits, err := rq.LimitWaits("db5")
+	if err != nil || waits["db"] < 20*time.Millisecond {
+		t.Errorf("Expected db5 to wait on the db limit; got %v (%v)", waits, err)
+	}
+
+	if waits, _ = rq.LimitWaits("db0"); waits["db"] != 0 {
+		t.Errorf("Expected db0 not to wait; got %v", waits)
+	}
+
+	if _, err = rq.LimitWaits("typo"); !errors.Is(err, ErrQNoTask) {
+		t.Errorf("Expected ErrQNoTask; got %v", err)
+	}
+
+	if err = rq.Uses("typo", "db"); !errors.Is(err, ErrQNoTask) {
+		t.Errorf("Expected ErrQNoTask; got %v", err)
+	}
+
+	// ----------
+	// Sense-style tasks still need passes.
+
+	rq = NewInitQ()
+	rq.SetParallel(4)
+
+	var mu sync.Mutex
+	var config bool
+
+	rq.Add("server", func() ReqResult {
+		mu.Lock()
+		defer mu.Unlock()
+		if !config {
+			return TryAgain
+		}
+		return Satisfied
+	})
+	rq.Add("config", func() ReqResult {
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		config = true
+		return Satisfied
+	})
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if rq.Passes() != 2 {
+		t.Errorf("Expected 2 passes; got %d", rq.Passes())
+	}
+
+	// ----------
+	// A Stop lets the running tasks finish, and launches nothing further.
+
+	rq = NewInitQ()
+	rq.SetParallel(2)
+
+	var finished atomic.Bool
+	rq.Add("stopper", func() ReqResult { return Stop })
+	rq.Add("slow", func() ReqResult { time.Sleep(20 * time.Millisecond); finished.Store(true); return Satisfied })
+	rq.Add("never", func() ReqResult { t.Errorf("A task was run after the Stop"); return Satisfied }, "stopper")
+
+	if err := rq.Process(); err != ErrQStopped {
+		t.Errorf("Expected ErrQStopped; got %v", err)
+	}
+
+	if !finished.Load() || rq.States()["slow"] != Satisfied {
+		t.Errorf("Expected the running task to finish")
+	}
+
+	// ----------
+	// A panic is re-raised.
+
+	rq = NewInitQ()
+	rq.SetParallel(2)
+
+	rq.Add("panics", func() ReqResult { panic("boom") })
+	rq.Add("fine", func() ReqResult { return Satisfied })
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("Expected the panic to be re-raised; got %v", r)
+			}
+		}()
+		rq.Process()
+	}()
+
+	// ----------
+	// Limits must be defined.
+
+	BehaveUnresolvIsErr = true
+
+	rq = NewInitQ()
+	rq.SetParallel(2)
+
+	rq.Add("one", func() ReqResult { return Satisfied })
+	rq.Uses("one", "disk")
+
+	if err := rq.Process(); err == nil || !strings.Contains(err.Error(), "disk") {
+		t.Errorf("Expected an undefined limit error; got %v", err)
+	}
+
+	rq.SetLimit("disk", 0)
+
+	if err := rq.Process(); err == nil || !strings.Contains(err.Error(), "disk") {
+		t.Errorf("Expected a zero limit error; got %v", err)
+	}
+
+	BehaveUnresolvIsErr = false
+
+}
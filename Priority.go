@@ -0,0 +1,148 @@
+package initq
+
+import (
+	"fmt"
+	"slices"
+)
+
+/*
+	Ordering of a pass:
+
+	- Tasks are (by default) run in the order they were added - or in the
+	  learned order (see LearnOrder).
+	- A task with a higher priority is run before those with a lower
+	  priority. The default priority is zero.
+	- A preference (PreferBefore / PreferAfter) runs one task before another
+	  when both are ready in the same pass. It outranks priority, but it is
+	  not a dependency: a task is never held back by a preference. Cycles of
+	  preferences are broken by priority (and then by order).
+	- The order is recomputed on each pass, and is deterministic.
+*/
+
+/* ======================================================================== */
+
+// SetPriority sets the priority of the named task. Among the tasks that are
+// ready to run in a pass, those with a higher priority are run first.
+//
+// An error (ErrQNoTask) is returned if the name does not match a task.
+func (rq *InitQ) SetPriority(name string, priority int) (err error) {
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	rqi := rq.item(name)
+	if rqi == nil {
+		return fmt.Errorf("%w: %s", ErrQNoTask, name)
+	}
+
+	rqi.priority = priority
+
+	return
+}
+
+/* ======================================================================== */
+
+// PreferBefore is a soft ordering hint: the named task is run before each of
+// the others when they are ready in the same pass.
+//
+// An error (ErrQNoTask) is returned if any name does not match a task.
+func (rq *InitQ) PreferBefore(name string, others ...string) (err error) {
+	return rq.prefer(name, others, true)
+}
+
+/* ======================================================================== */
+
+// PreferAfter is a soft ordering hint: the named task is run after each of
+// the others when they are ready in the same pass.
+//
+// An error (ErrQNoTask) is returned if any name does not match a task.
+func (rq *InitQ) PreferAfter(name string, others ...string) (err error) {
+	return rq.prefer(name, others, false)
+}
+
+/* ======================================================================== */
+
+// prefer records the preferences of the named task - before (or after) the
+// others. Nothing is recorded if any of the names are invalid.
+func (rq *InitQ) prefer(name string, others []string, before bool) (err error) {
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	for _, n := range append([]string{name}, others...) {
+		if rq.item(n) == nil {
+			return fmt.Errorf("%w: %s", ErrQNoTask, n)
+		}
+	}
+
+	for _, o := range others {
+
+		first, then := rq.item(name), o
+		if !before {
+			first, then = rq.item(o), name
+		}
+
+		if first.name != then && !slices.Contains(first.before, then) {
+			first.before = append(first.before, then)
+		}
+	}
+
+	return
+}
+
+/* ======================================================================== */
+
+// schedule returns the Q in the order of the next pass. Without a learned
+// order, priorities or preferences this is the Q itself. It must be called
+// with the lock held.
+func (rq *InitQ) schedule() (order []*initQItem) {
+
+	base := rq.q
+	if len(rq.rank) > 0 {
+		base = rq.ranked()
+	}
+
+	if !slices.ContainsFunc(base, func(rqi *initQItem) bool { return rqi.priority != 0 || len(rqi.before) > 0 }) {
+		return base
+	}
+
+	// The number of (not yet placed) tasks preferred before each task.
+	waiting := make(map[string]int)
+	for _, rqi := range base {
+		for _, b := range rqi.before {
+			waiting[b]++
+		}
+	}
+
+	pending := slices.Clone(base)
+	for len(pending) > 0 {
+
+		// The first task of the highest priority that has nothing (left)
+		// preferred before it. A cycle (with nothing free) takes the first
+		// of the highest priority regardless.
+		best := -1
+		for _, free := range []bool{true, false} {
+			for i, rqi := range pending {
+				if free && waiting[rqi.name] > 0 {
+					continue
+				}
+				if best < 0 || rqi.priority > pending[best].priority {
+					best = i
+				}
+			}
+			if best >= 0 {
+				break
+			}
+		}
+
+		next := pending[best]
+		pending = slices.Delete(pending, best, best+1)
+		order = append(order, next)
+
+		for _, b := range next.before {
+			waiting[b]--
+		}
+	}
+
+	return
+}
@@ -0,0 +1,122 @@
+package initq
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+/* ======================================================================== */
+
+func TestPriority(t *testing.T) {
+
+	var rq *InitQ
+
+	// called returns the task labels in the order they were called.
+	called := func(rq *InitQ) (names []string) {
+		for _, a := range rq.Trace() {
+			names = append(names, a.Task)
+		}
+		return
+	}
+
+	ok := func() ReqResult { return Satisfied }
+
+	// ----------
+	// Priority, then preferences.
+
+	build := func() *InitQ {
+
+		rq := NewInitQ()
+
+		rq.Add("config", ok)
+		rq.Add("server", ok)
+		rq.Add("metrics", ok)
+		rq.Add("log", ok)
+
+		if err := rq.SetPriority("log", 10); err != nil {
+			t.Errorf("Unexpected error %s", err.Error())
+		}
+
+		if err := rq.PreferAfter("server", "metrics"); err != nil {
+			t.Errorf("Unexpected error %s", err.Error())
+		}
+
+		return rq
+	}
+
+	rq = build()
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if order := called(rq); !slices.Equal(order, []string{"log", "config", "metrics", "server"}) {
+		t.Errorf("Unexpected order %v", order)
+	}
+
+	// Deterministic.
+	again := build()
+	again.Process()
+
+	if !slices.Equal(called(rq), called(again)) {
+		t.Errorf("The order differs between runs")
+	}
+
+	// The Status order is not changed.
+	if st := rq.Status(); st[0].Name != "config" || st[3].Name != "log" {
+		t.Errorf("Unexpected status order %+v", st)
+	}
+
+	// ----------
+	// A preference is not a dependency.
+
+	rq = NewInitQ()
+
+	rq.Add("server", ok)
+	rq.Add("metrics", ok, "server")
+	rq.Add("setup", ok)
+
+	rq.PreferBefore("metrics", "server", "setup")
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if order := called(rq); !slices.Equal(order, []string{"server", "setup", "metrics"}) {
+		t.Errorf("Unexpected order %v", order)
+	}
+
+	// ----------
+	// A cycle of preferences (broken by priority).
+
+	rq = NewInitQ()
+
+	rq.Add("black", ok)
+	rq.Add("white", ok)
+	rq.Add("grey", ok)
+
+	rq.PreferBefore("black", "white")
+	rq.PreferBefore("white", "black")
+	rq.SetPriority("white", 1)
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if order := called(rq); !slices.Equal(order, []string{"grey", "white", "black"}) {
+		t.Errorf("Unexpected order %v", order)
+	}
+
+	// ----------
+	// Unknown names.
+
+	if err := rq.SetPriority("typo", 1); !errors.Is(err, ErrQNoTask) {
+		t.Errorf("Expected ErrQNoTask; got %v", err)
+	}
+
+	if err := rq.PreferAfter("black", "grey", "typo"); !errors.Is(err, ErrQNoTask) {
+		t.Errorf("Expected ErrQNoTask; got %v", err)
+	}
+
+}
@@ -14,9 +14,51 @@ import (
 // in a way that does not cause Fatal() assertions.
 //
 // In addition to the standard Error() method, this includes an
-// UnresolvedTasks() method that lists the tasks that could not be completed.
+// UnresolvedTasks() method that lists the tasks that could not be completed,
+// and Diagnostics() / RootCauses() methods that explain why.
 type QUnresolvable struct {
 	unsat []string
+
+	// diags are the per-task details (set by process).
+	diags []TaskDiagnostic
+
+	// stalled is true when processing was abandoned because it stopped
+	// making progress (rather than exhausting the pass budget).
+	stalled bool
+}
+
+/* ------------------------------------------------------------------------ */
+
+// TaskDiagnostic explains why a single task could not be Satisfied.
+type TaskDiagnostic struct {
+	// Name is the task label.
+	Name string
+
+	// UnmetDeps are the explicit dependencies that were not Satisfied.
+	UnmetDeps []string
+
+	// Invocations is the number of times the task function was called.
+	// Zero means it was always blocked by its explicit dependencies.
+	Invocations int
+
+	// LastResult is the result of the last task function call (UnRun if it
+	// was never called).
+	LastResult ReqResult
+
+	// WaitingOn are the tasks that the task said (with Task.WaitingOn) it
+	// was waiting on, and that were not Satisfied.
+	WaitingOn []string
+
+	// Reason is what the task said (with Task.TryAgainBecause) it was
+	// waiting for.
+	Reason string
+
+	// RootCause is true when the task is a cause (rather than a victim) of
+	// the unresolved Q. This is a task whose own function was called but
+	// never returned Satisfied (without saying what it was waiting on) - or
+	// a task in a dependency cycle (that is not blocked by some other root
+	// cause).
+	RootCause bool
 }
 
 /* ======================================================================== */
@@ -44,16 +86,37 @@ func newQUnresolvable(remains []string) (err *QUnresolvable) {
 // Error returns a single message that satisfies the error interface.
 func (qur QUnresolvable) Error() (msg string) {
 
+	what := "cannot be satisfied"
+	if qur.stalled {
+		what = "stalled"
+	}
+
 	if len(qur.unsat) > 0 {
-		msg = fmt.Sprintf("run Q cannot be satisfied (%s remain)", strings.Join(qur.unsat, ","))
+		msg = fmt.Sprintf("run Q %s (%s remain)", what, strings.Join(qur.unsat, ","))
 	} else {
-		msg = "run Q cannot be satisfied"
+		msg = "run Q " + what
 	}
 	return
 }
 
 /* ======================================================================== */
 
+// Stalled reports if processing was abandoned because passes stopped making
+// progress (see SetStallPasses), rather than because the pass budget was
+// exhausted.
+func (qur QUnresolvable) Stalled() bool {
+	return qur.stalled
+}
+
+/* ======================================================================== */
+
+// Is allows a stalled QUnresolvable to match ErrQStalled (with errors.Is).
+func (qur QUnresolvable) Is(target error) bool {
+	return qur.stalled && target == ErrQStalled
+}
+
+/* ======================================================================== */
+
 // UnresolvedTasks returns the tasks that were not satisfied. This eliminates
 // the need to parse them out of the Error() output.
 func (qur QUnresolvable) UnresolvedTasks() (unsat []string) {
@@ -63,3 +126,101 @@ func (qur QUnresolvable) UnresolvedTasks() (unsat []string) {
 	unsat = qur.unsat
 	return
 }
+
+/* ======================================================================== */
+
+// Diagnostics returns the per-task details of the unresolved tasks. It is
+// empty if the error was not created by processing a Q.
+func (qur QUnresolvable) Diagnostics() []TaskDiagnostic {
+	return slices.Clone(qur.diags)
+}
+
+/* ======================================================================== */
+
+// RootCauses returns the unresolved tasks that are root causes (see
+// TaskDiagnostic). The remaining unresolved tasks are (merely) blocked
+// downstream of these.
+func (qur QUnresolvable) RootCauses() (roots []string) {
+
+	for _, d := range qur.diags {
+		if d.RootCause {
+			roots = append(roots, d.Name)
+		}
+	}
+
+	return
+}
+
+/* ======================================================================== */
+
+// diagnose builds the diagnostics of the unresolved tasks. It must be called
+// with the lock held.
+func (rq *InitQ) diagnose(remaining []string) (diags []TaskDiagnostic) {
+
+	index := make(map[string]int)
+
+	for _, name := range remaining {
+
+		rqi := rq.item(name)
+		d := TaskDiagnostic{Name: name, Invocations: rqi.invocations, LastResult: rqi.last, Reason: rqi.reason}
+
+		for _, dep := range rqi.deps {
+			if !rq.satisfied(dep) {
+				d.UnmetDeps = append(d.UnmetDeps, dep)
+			}
+		}
+
+		for _, w := range rqi.waits {
+			if !rq.satisfied(w) {
+				d.WaitingOn = append(d.WaitingOn, w)
+			}
+		}
+
+		// The function itself never satisfied.
+		d.RootCause = d.failed()
+
+		index[name] = len(diags)
+		diags = append(diags, d)
+	}
+
+	// A blocked task is downstream if (through unmet dependencies or waits)
+	// it reaches a root cause. Those that do not are blocked by each other
+	// (a cycle) and are root causes themselves.
+	var reaches func(name string, seen map[string]bool) bool
+	reaches = func(name string, seen map[string]bool) bool {
+
+		i, ok := index[name]
+		if !ok || seen[name] {
+			return false
+		}
+		seen[name] = true
+
+		if diags[i].failed() {
+			return true
+		}
+
+		for _, dep := range slices.Concat(diags[i].UnmetDeps, diags[i].WaitingOn) {
+			if reaches(dep, seen) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for i := range diags {
+		if !diags[i].RootCause && !reaches(diags[i].Name, make(map[string]bool)) {
+			diags[i].RootCause = true
+		}
+	}
+
+	return
+}
+
+/* ======================================================================== */
+
+// failed reports if the task function itself was called and never returned
+// Satisfied - and did not say it was waiting on another (unmet) task.
+func (d TaskDiagnostic) failed() bool {
+	return d.Invocations > 0 && d.LastResult != Satisfied && len(d.WaitingOn) == 0
+}
@@ -1,6 +1,7 @@
 package initq
 
 import (
+	"slices"
 	"strings"
 	"testing"
 )
@@ -86,3 +87,65 @@ func TestQUnresolvable(t *testing.T) {
 	}
 
 }
+
+/* ======================================================================== */
+
+func TestQUnresolvableDiagnostics(t *testing.T) {
+
+	// ----------
+	// A task that never satisfies (scheduler), with an explicit dependant
+	// (report), and a cycle (black / white).
+
+	rq := NewInitQ()
+	cd := new(coredata)
+
+	rq.Add("config", cd.ReadConfigFile)
+	rq.Add("cmdline", cd.ParseCommandLIne)
+	rq.Add("dbconn", cd.SetupDBConnection)
+	rq.Add("server", cd.SetupServer)
+	rq.Add("scheduler", cd.StartScheduler)
+	rq.Add("report", func() ReqResult { return Satisfied }, "scheduler", "cmdline")
+	rq.Add("black", func() ReqResult { return Satisfied }, "white")
+	rq.Add("white", func() ReqResult { return Satisfied }, "black")
+
+	err := rq.TryProcess()
+
+	qur, ok := err.(*QUnresolvable)
+	if !ok {
+		t.Fatalf("Expected a *QUnresolvable error; got %v", err)
+	}
+
+	roots := qur.RootCauses()
+	if !slices.Equal(roots, []string{"scheduler", "black", "white"}) {
+		t.Errorf("Unexpected root causes %v", roots)
+	}
+
+	diags := make(map[string]TaskDiagnostic)
+	for _, d := range qur.Diagnostics() {
+		diags[d.Name] = d
+	}
+
+	if len(diags) != 4 {
+		t.Errorf("Expected 4 diagnostics; got %d", len(diags))
+	}
+
+	if d := diags["scheduler"]; d.Invocations != rq.Passes() || d.LastResult != TryAgain || len(d.UnmetDeps) != 0 {
+		t.Errorf("Unexpected scheduler diagnostic %+v", d)
+	}
+
+	if d := diags["report"]; d.Invocations != 0 || d.LastResult != UnRun || d.RootCause || !slices.Equal(d.UnmetDeps, []string{"scheduler"}) {
+		t.Errorf("Unexpected report diagnostic %+v", d)
+	}
+
+	if d := diags["black"]; !slices.Equal(d.UnmetDeps, []string{"white"}) || !d.RootCause {
+		t.Errorf("Unexpected black diagnostic %+v", d)
+	}
+
+	// ----------
+	// An error created without a Q has no diagnostics.
+
+	if len(newQUnresolvable([]string{"one"}).Diagnostics()) != 0 {
+		t.Errorf("Unexpected diagnostics")
+	}
+
+}
@@ -0,0 +1,277 @@
+package initq
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+/* ------------------------------------------------------------------------ */
+
+// Span is a single task function call (as recorded by a Recorder).
+type Span struct {
+	// Task is the task label.
+	Task string
+
+	// Pass is the (1-based) pass of the Q the call was made in.
+	Pass int
+
+	// Result is the task function result.
+	Result ReqResult
+
+	// Start and End are when the call was made and returned.
+	Start time.Time
+	End   time.Time
+}
+
+/* ------------------------------------------------------------------------ */
+
+// SpanExporter receives each Span as it is recorded. It is the adapter used
+// to forward spans to a tracing system (such as OpenTelemetry) without this
+// module depending on it. For example:
+//
+//	type otelExporter struct {
+//		ctx    context.Context
+//		tracer trace.Tracer
+//	}
+//
+//	func (oe otelExporter) ExportSpan(s initq.Span) {
+//		_, span := oe.tracer.Start(oe.ctx, s.Task, trace.WithTimestamp(s.Start))
+//		span.SetAttributes(
+//			attribute.Int("initq.pass", s.Pass),
+//			attribute.String("initq.result", s.Result.String()))
+//		span.End(trace.WithTimestamp(s.End))
+//	}
+type SpanExporter interface {
+	ExportSpan(s Span)
+}
+
+/* ------------------------------------------------------------------------ */
+
+// Recorder captures each task function call as a Span. It is an Observer.
+// The spans can be written as a Chrome trace_event file (viewable in
+// Perfetto or chrome://tracing), or forwarded to SpanExporters as they are
+// recorded.
+type Recorder struct {
+	// exporters receive each span.
+	exporters []SpanExporter
+
+	// mu protects spans and the process times.
+	mu sync.Mutex
+
+	// spans are the recorded spans (in order).
+	spans []Span
+
+	// start and end are the times of the (last) process run.
+	start time.Time
+	end   time.Time
+}
+
+/* ------------------------------------------------------------------------ */
+
+// chromeEvent is a single Chrome trace_event (complete "X" or metadata "M"
+// event).
+type chromeEvent struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat"`
+	Ph   string         `json:"ph"`
+	Ts   int64          `json:"ts"`
+	Dur  int64          `json:"dur"`
+	Pid  int            `json:"pid"`
+	Tid  int            `json:"tid"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+/* ------------------------------------------------------------------------ */
+
+// chromeTrace is the Chrome trace_event file (JSON object format).
+type chromeTrace struct {
+	TraceEvents     []chromeEvent `json:"traceEvents"`
+	DisplayTimeUnit string        `json:"displayTimeUnit"`
+}
+
+/* ======================================================================== */
+
+// NewRecorder creates a Recorder that (optionally) forwards spans to the
+// exporters.
+//
+//	rec := initq.NewRecorder()
+//	iq.Observe(rec.Observe)
+func NewRecorder(exporters ...SpanExporter) (r *Recorder) {
+
+	r = new(Recorder)
+	r.exporters = exporters
+
+	return
+}
+
+/* ======================================================================== */
+
+// Observe is the Observer that records the spans.
+func (r *Recorder) Observe(ev Event) {
+
+	r.mu.Lock()
+
+	switch ev.Kind {
+	case EventProcessStart:
+		r.start = ev.Time
+	case EventProcessEnd:
+		r.end = ev.Time
+	case EventTaskEnd:
+		s := Span{Task: ev.Task, Pass: ev.Pass, Result: ev.Result, Start: ev.Time.Add(-ev.Elapsed), End: ev.Time}
+		r.spans = append(r.spans, s)
+
+		// Exporters are called without the lock (they may be slow).
+		r.mu.Unlock()
+		for _, e := range r.exporters {
+			e.ExportSpan(s)
+		}
+		return
+	}
+
+	r.mu.Unlock()
+}
+
+/* ======================================================================== */
+
+// Spans returns the recorded spans.
+func (r *Recorder) Spans() []Span {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.spans)
+}
+
+/* ======================================================================== */
+
+// WriteChromeTrace writes the spans in the Chrome trace_event (JSON) format.
+// Each pass of the Q is a separate 'thread' so passes are shown on their own
+// rows. The whole process run is shown on row zero.
+//
+// Spans that overlap (parallel processing) are given their own lanes. The
+// first lane of a pass is the pass number, and further lanes are numbered
+// after the last pass. The rows are then named (and sorted) by pass and
+// lane.
+func (r *Recorder) WriteChromeTrace(w io.Writer) error {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ct := chromeTrace{TraceEvents: make([]chromeEvent, 0), DisplayTimeUnit: "ms"}
+
+	origin := r.start
+	if origin.IsZero() && len(r.spans) > 0 {
+		origin = r.spans[0].Start
+	}
+
+	if !r.start.IsZero() && !r.end.IsZero() {
+		ct.TraceEvents = append(ct.TraceEvents, chromeEvent{
+			Name: "process",
+			Cat:  "initq",
+			Ph:   "X",
+			Ts:   0,
+			Dur:  r.end.Sub(r.start).Microseconds(),
+			Pid:  1,
+			Tid:  0,
+		})
+	}
+
+	lanes, width := r.lanes()
+
+	last := 0
+	for _, s := range r.spans {
+		last = max(last, s.Pass)
+	}
+
+	// The row of each pass / lane. The first lane of a pass is the pass
+	// number. Further lanes follow the last pass.
+	tids := make(map[[2]int]int)
+	for i, s := range r.spans {
+
+		key := [2]int{s.Pass, lanes[i]}
+		tid, ok := tids[key]
+		if !ok {
+			tid = s.Pass
+			if lanes[i] > 0 {
+				last++
+				tid = last
+			}
+			tids[key] = tid
+		}
+
+		ct.TraceEvents = append(ct.TraceEvents, chromeEvent{
+			Name: s.Task,
+			Cat:  "initq",
+			Ph:   "X",
+			Ts:   s.Start.Sub(origin).Microseconds(),
+			Dur:  s.End.Sub(s.Start).Microseconds(),
+			Pid:  1,
+			Tid:  tid,
+			Args: map[string]any{"pass": s.Pass, "result": s.Result.String()},
+		})
+	}
+
+	// With lanes, the rows are named and sorted (by pass, then lane) with
+	// metadata events. A trace without overlapping spans has none.
+	if width == 1 {
+		return json.NewEncoder(w).Encode(ct)
+	}
+
+	for _, key := range slices.SortedFunc(maps.Keys(tids), func(a, b [2]int) int { return (a[0]*width + a[1]) - (b[0]*width + b[1]) }) {
+
+		name := fmt.Sprintf("pass %d", key[0])
+		if key[1] > 0 {
+			name += fmt.Sprintf(" (lane %d)", key[1]+1)
+		}
+
+		ct.TraceEvents = append(ct.TraceEvents,
+			chromeEvent{Name: "thread_name", Ph: "M", Pid: 1, Tid: tids[key], Args: map[string]any{"name": name}},
+			chromeEvent{Name: "thread_sort_index", Ph: "M", Pid: 1, Tid: tids[key], Args: map[string]any{"sort_index": key[0]*width + key[1]}})
+	}
+
+	return json.NewEncoder(w).Encode(ct)
+}
+
+/* ======================================================================== */
+
+// lanes assigns each span a lane (within its pass) so that the spans of a
+// lane do not overlap. The return is the lane of each span, and the most
+// lanes of any pass. It must be called with the lock held.
+func (r *Recorder) lanes() (lanes []int, width int) {
+
+	lanes = make([]int, len(r.spans))
+	width = 1
+
+	// The end of the last span in each lane (by pass).
+	ends := make(map[int][]time.Time)
+
+	// Spans are recorded as they end. Lanes are filled in order of start.
+	order := make([]int, len(r.spans))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return r.spans[a].Start.Compare(r.spans[b].Start) })
+
+	for _, i := range order {
+
+		s := r.spans[i]
+
+		lane := slices.IndexFunc(ends[s.Pass], func(end time.Time) bool { return !end.After(s.Start) })
+		if lane < 0 {
+			lane = len(ends[s.Pass])
+			ends[s.Pass] = append(ends[s.Pass], s.End)
+		} else {
+			ends[s.Pass][lane] = s.End
+		}
+
+		lanes[i] = lane
+		width = max(width, len(ends[s.Pass]))
+	}
+
+	return
+}
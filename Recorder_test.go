@@ -0,0 +1,151 @@
+package initq
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+/* ======================================================================== */
+
+// memExporter is an in-memory SpanExporter (standing in for a tracer).
+type memExporter struct {
+	mu    sync.Mutex
+	spans []Span
+}
+
+func (me *memExporter) ExportSpan(s Span) {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	me.spans = append(me.spans, s)
+}
+
+/* ======================================================================== */
+
+func TestRecorder(t *testing.T) {
+
+	rq := NewInitQ()
+	me := new(memExporter)
+	rec := NewRecorder(me)
+	rq.Observe(rec.Observe)
+
+	cd := new(coredata)
+	rq.Add("config", cd.ReadConfigFile)
+	rq.Add("cmdline", cd.ParseCommandLIne)
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	// ----------
+	// The spans (and the exported copies).
+
+	spans := rec.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 spans; got %d", len(spans))
+	}
+
+	if spans[0].Task != "config" || spans[0].Result != TryAgain || spans[0].Pass != 1 {
+		t.Errorf("Unexpected first span %+v", spans[0])
+	}
+
+	if spans[2].Task != "config" || spans[2].Result != Satisfied || spans[2].Pass != 2 {
+		t.Errorf("Unexpected last span %+v", spans[2])
+	}
+
+	for _, s := range spans {
+		if s.End.Before(s.Start) {
+			t.Errorf("Span %s ends before it starts", s.Task)
+		}
+	}
+
+	if len(me.spans) != 3 || me.spans[1].Task != "cmdline" {
+		t.Errorf("Unexpected exported spans %+v", me.spans)
+	}
+
+	// ----------
+	// The Chrome trace.
+
+	var buf bytes.Buffer
+	if err := rec.WriteChromeTrace(&buf); err != nil {
+		t.Errorf("Unexpected WriteChromeTrace error - %s", err.Error())
+	}
+
+	var ct chromeTrace
+	if err := json.Unmarshal(buf.Bytes(), &ct); err != nil {
+		t.Fatalf("Invalid trace JSON - %s", err.Error())
+	}
+
+	// process + 3 task spans.
+	if len(ct.TraceEvents) != 4 {
+		t.Fatalf("Expected 4 trace events; got %d", len(ct.TraceEvents))
+	}
+
+	if ct.TraceEvents[0].Name != "process" || ct.TraceEvents[3].Tid != 2 || ct.TraceEvents[3].Ph != "X" {
+		t.Errorf("Unexpected trace events %+v", ct.TraceEvents)
+	}
+
+	if ct.TraceEvents[3].Args["result"] != "satisfied" {
+		t.Errorf("Unexpected args %v", ct.TraceEvents[3].Args)
+	}
+
+	// ----------
+	// Parallel spans overlap. Each is given its own lane (row), and the
+	// spans of a row never overlap.
+
+	rq = NewInitQ()
+	rq.SetParallel(3)
+	rec = NewRecorder()
+	rq.Observe(rec.Observe)
+
+	for _, name := range []string{"db", "cache", "queue"} {
+		rq.Add(name, func() ReqResult {
+			time.Sleep(20 * time.Millisecond)
+			return Satisfied
+		})
+	}
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	buf.Reset()
+	if err := rec.WriteChromeTrace(&buf); err != nil {
+		t.Errorf("Unexpected WriteChromeTrace error - %s", err.Error())
+	}
+
+	ct = chromeTrace{}
+	if err := json.Unmarshal(buf.Bytes(), &ct); err != nil {
+		t.Fatalf("Invalid trace JSON - %s", err.Error())
+	}
+
+	rows := make(map[int][]chromeEvent)
+	names := make(map[int]string)
+	for _, ev := range ct.TraceEvents {
+		switch {
+		case ev.Ph == "X" && ev.Name != "process":
+			rows[ev.Tid] = append(rows[ev.Tid], ev)
+		case ev.Ph == "M" && ev.Name == "thread_name":
+			names[ev.Tid], _ = ev.Args["name"].(string)
+		}
+	}
+
+	if len(rows) != 3 {
+		t.Errorf("Expected 3 rows for 3 overlapping spans; got %d", len(rows))
+	}
+
+	for tid, evs := range rows {
+		for i := 1; i < len(evs); i++ {
+			if evs[i].Ts < evs[i-1].Ts+evs[i-1].Dur {
+				t.Errorf("Overlapping spans on row %d", tid)
+			}
+		}
+		if !strings.HasPrefix(names[tid], "pass 1") {
+			t.Errorf("Unexpected name %q for row %d", names[tid], tid)
+		}
+	}
+
+}
@@ -0,0 +1,249 @@
+package initq
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+/* ------------------------------------------------------------------------ */
+
+// RenderMode selects the output style of a Renderer.
+type RenderMode int
+
+/* ------------------------------------------------------------------------ */
+
+const (
+	// RenderPlain writes one line per task state change. It is suitable for
+	// logs, pipes, and other non-TTY output.
+	RenderPlain RenderMode = iota
+
+	// RenderSpinner keeps a single (redrawn) status line with a spinner, and
+	// writes a line as each task completes. It requires a terminal.
+	RenderSpinner
+)
+
+/* ------------------------------------------------------------------------ */
+
+// The task states shown by a Renderer.
+const (
+	displayPending   = "pending"
+	displayWaiting   = "waiting on deps"
+	displayRunning   = "running"
+	displaySatisfied = "satisfied"
+	displayStopped   = "stopped"
+)
+
+/* ------------------------------------------------------------------------ */
+
+// spinFrames are the spinner animation frames.
+var spinFrames = []string{"|", "/", "-", "\\"}
+
+/* ------------------------------------------------------------------------ */
+
+// Renderer shows the progress of a processing Q on an io.Writer. It is
+// driven by process events (it is an Observer).
+type Renderer struct {
+	// w is the output.
+	w io.Writer
+
+	// mode is the output style.
+	mode RenderMode
+
+	// interval is the spinner redraw interval.
+	interval time.Duration
+
+	// mu protects all below (the spinner redraws from its own goroutine).
+	mu sync.Mutex
+
+	// states is the displayed state of each task.
+	states map[string]string
+
+	// last is the most recent event (used for the status line).
+	last Event
+
+	// frame is the current spinner frame.
+	frame int
+
+	// stop ends the spinner goroutine (nil when not running).
+	stop chan struct{}
+}
+
+/* ======================================================================== */
+
+// NewRenderer creates a Renderer. Use IsTerminal to choose the mode:
+//
+//	mode := initq.RenderPlain
+//	if initq.IsTerminal(os.Stderr) {
+//		mode = initq.RenderSpinner
+//	}
+//	iq.Observe(initq.NewRenderer(os.Stderr, mode).Observe)
+func NewRenderer(w io.Writer, mode RenderMode) (r *Renderer) {
+
+	r = new(Renderer)
+	r.w = w
+	r.mode = mode
+	r.interval = 100 * time.Millisecond
+	r.states = make(map[string]string)
+
+	return
+}
+
+/* ======================================================================== */
+
+// IsTerminal reports if the file is a terminal (character device).
+func IsTerminal(f *os.File) bool {
+
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+/* ======================================================================== */
+
+// Observe is the Observer that drives the output.
+func (r *Renderer) Observe(ev Event) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.last = ev
+
+	switch ev.Kind {
+	case EventProcessStart:
+		for _, task := range ev.Pending {
+			r.set(task, displayPending)
+		}
+		if r.mode == RenderSpinner {
+			r.startSpinner()
+		}
+	case EventTaskBlocked:
+		r.set(ev.Task, displayWaiting)
+	case EventTaskStart:
+		r.set(ev.Task, displayRunning)
+	case EventTaskEnd:
+		switch ev.Result {
+		case Satisfied:
+			r.set(ev.Task, displaySatisfied)
+		case Stop:
+			r.set(ev.Task, displayStopped)
+		default:
+			r.set(ev.Task, displayPending)
+		}
+	case EventProcessEnd:
+		r.stopSpinner()
+		if r.mode == RenderSpinner {
+			fmt.Fprint(r.w, "\r\033[K")
+		}
+		if ev.Err != nil {
+			fmt.Fprintf(r.w, "initialization failed: %s\n", ev.Err.Error())
+		}
+		return
+	}
+
+	if r.mode == RenderSpinner {
+		r.draw()
+	}
+}
+
+/* ======================================================================== */
+
+// Close stops the spinner (if running). It is only needed if processing
+// does not end normally (EventProcessEnd stops the spinner).
+func (r *Renderer) Close() {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stopSpinner()
+}
+
+/* ======================================================================== */
+
+// set changes the displayed state of a task. Output only happens on change.
+// It must be called with the lock held.
+func (r *Renderer) set(task string, state string) {
+
+	if r.states[task] == state {
+		return
+	}
+	r.states[task] = state
+
+	switch r.mode {
+	case RenderPlain:
+		fmt.Fprintf(r.w, "%s: %s\n", task, state)
+	case RenderSpinner:
+		// Only the final states are permanent lines.
+		if state == displaySatisfied || state == displayStopped {
+			fmt.Fprintf(r.w, "\r\033[K%s: %s\n", task, state)
+		}
+	}
+}
+
+/* ======================================================================== */
+
+// draw redraws the spinner status line. It must be called with the lock
+// held.
+func (r *Renderer) draw() {
+
+	status := fmt.Sprintf("initializing (%d/%d)", r.last.Satisfied, r.last.Total)
+	if len(r.last.Task) > 0 {
+		status = fmt.Sprintf("initializing: %s %s (%d/%d)",
+			r.last.Task, r.states[r.last.Task], r.last.Satisfied, r.last.Total)
+	}
+
+	fmt.Fprintf(r.w, "\r\033[K%s %s", spinFrames[r.frame%len(spinFrames)], status)
+}
+
+/* ======================================================================== */
+
+// startSpinner starts the redraw goroutine. It must be called with the lock
+// held.
+func (r *Renderer) startSpinner() {
+
+	r.stopSpinner()
+
+	stop := make(chan struct{})
+	r.stop = stop
+
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				r.mu.Lock()
+				// The spinner may have been stopped while waiting on the lock.
+				select {
+				case <-stop:
+					r.mu.Unlock()
+					return
+				default:
+				}
+				r.frame++
+				r.draw()
+				r.mu.Unlock()
+			}
+		}
+	}()
+}
+
+/* ======================================================================== */
+
+// stopSpinner stops the redraw goroutine. It must be called with the lock
+// held.
+func (r *Renderer) stopSpinner() {
+
+	if r.stop != nil {
+		close(r.stop)
+		r.stop = nil
+	}
+}
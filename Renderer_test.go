@@ -0,0 +1,145 @@
+package initq
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+/* ======================================================================== */
+
+// syncBuffer is a bytes.Buffer that is safe for the spinner goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (sb *syncBuffer) Write(p []byte) (int, error) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.Write(p)
+}
+
+func (sb *syncBuffer) String() string {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.String()
+}
+
+/* ======================================================================== */
+
+func TestRenderer(t *testing.T) {
+
+	var rq *InitQ
+	var out *syncBuffer
+
+	// ----------
+	// Plain mode - one line per change.
+
+	rq = NewInitQ()
+	out = new(syncBuffer)
+	rq.Observe(NewRenderer(out, RenderPlain).Observe)
+
+	cd := new(coredata)
+	rq.Add("config", cd.ReadConfigFile)
+	rq.Add("cmdline", cd.ParseCommandLIne)
+	rq.Add("dbconn", cd.SetupDBConnection, "config")
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	expected := strings.Join([]string{
+		"config: pending",
+		"cmdline: pending",
+		"dbconn: pending",
+		"config: running",
+		"config: pending",
+		"cmdline: running",
+		"cmdline: satisfied",
+		"dbconn: waiting on deps",
+		"config: running",
+		"config: satisfied",
+		"dbconn: running",
+		"dbconn: satisfied",
+	}, "\n") + "\n"
+
+	if out.String() != expected {
+		t.Errorf("Unexpected plain output:\n%s", out.String())
+	}
+
+	// ----------
+	// A stop is shown (with the error).
+
+	rq = NewInitQ()
+	out = new(syncBuffer)
+	rq.Observe(NewRenderer(out, RenderPlain).Observe)
+
+	rq.Add("stopper", func() ReqResult { return Stop })
+	_ = rq.Process()
+
+	if !strings.Contains(out.String(), "stopper: stopped\ninitialization failed") {
+		t.Errorf("Unexpected stopped output:\n%s", out.String())
+	}
+
+	// ----------
+	// Spinner mode - redraws while a slow task runs.
+
+	rq = NewInitQ()
+	out = new(syncBuffer)
+	r := NewRenderer(out, RenderSpinner)
+	r.interval = time.Millisecond
+	rq.Observe(r.Observe)
+
+	rq.Add("slow", func() ReqResult {
+		time.Sleep(20 * time.Millisecond)
+		return Satisfied
+	})
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+	r.Close()
+
+	s := out.String()
+	if !strings.Contains(s, "initializing: slow running (0/1)") || !strings.Contains(s, "slow: satisfied\n") {
+		t.Errorf("Unexpected spinner output %q", s)
+	}
+
+	if strings.Count(s, "\r") < 4 {
+		t.Errorf("Expected the spinner to redraw; got %q", s)
+	}
+
+	// A spinner stopped while its goroutine waits on the lock does not draw.
+	out = new(syncBuffer)
+	r = NewRenderer(out, RenderSpinner)
+	r.interval = time.Millisecond
+
+	r.mu.Lock()
+	r.startSpinner()
+	time.Sleep(10 * time.Millisecond)
+	r.stopSpinner()
+	r.mu.Unlock()
+	time.Sleep(10 * time.Millisecond)
+
+	if s = out.String(); len(s) != 0 {
+		t.Errorf("Unexpected output from a stopped spinner %q", s)
+	}
+
+	// ----------
+	// A temp file is not a terminal.
+
+	f, err := os.CreateTemp(t.TempDir(), "tty")
+	if err != nil {
+		t.Fatalf("Unable to create a temp file - %s", err.Error())
+	}
+	defer f.Close()
+
+	if IsTerminal(f) {
+		t.Errorf("A regular file was reported as a terminal")
+	}
+
+}
@@ -1,5 +1,7 @@
 package initq
 
+import "fmt"
+
 /* ------------------------------------------------------------------------ */
 
 // ReqResult is the type returned by a requirement function. It is the type
@@ -29,3 +31,47 @@ const (
 	// error.
 	Stop
 )
+
+/* ======================================================================== */
+
+// String returns a (lower case) label for the result. It is used in traces,
+// reports, and other output.
+func (r ReqResult) String() string {
+
+	switch r {
+	case UnRun:
+		return "unrun"
+	case Satisfied:
+		return "satisfied"
+	case TryAgain:
+		return "tryagain"
+	case Stop:
+		return "stop"
+	}
+
+	return fmt.Sprintf("ReqResult(%d)", int(r))
+}
+
+/* ======================================================================== */
+
+// MarshalText implements encoding.TextMarshaler (using the String label).
+// This allows results to be used directly in JSON output.
+func (r ReqResult) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+/* ======================================================================== */
+
+// UnmarshalText implements encoding.TextUnmarshaler. It is the inverse of
+// MarshalText.
+func (r *ReqResult) UnmarshalText(text []byte) error {
+
+	for _, v := range []ReqResult{UnRun, Satisfied, TryAgain, Stop} {
+		if v.String() == string(text) {
+			*r = v
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown ReqResult %q", string(text))
+}
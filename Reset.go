@@ -0,0 +1,113 @@
+package initq
+
+import (
+	"fmt"
+	"slices"
+)
+
+/*
+	Re-processing a Q:
+
+	- Once processed to completion, calling Process again does nothing (all
+	  tasks are Satisfied). Use Reset to run all tasks again.
+	- After ErrQStopped, calling Process again keeps the Satisfied tasks,
+	  and re-attempts the task that returned Stop (and all others that were
+	  not Satisfied). This allows the caller to fix the problem and continue.
+	- After an unresolvable Q, calling Process again re-attempts all tasks
+	  that were not Satisfied.
+	- ResetTask can be used to re-run specific tasks (and optionally those
+	  that explicitly depend on them).
+*/
+
+/* ======================================================================== */
+
+// Reset returns all tasks in the Q to the initial (UnRun) state, and clears
+// the trace of the last run. The next Process call will run every task.
+func (rq *InitQ) Reset() {
+
+	if rq == nil {
+		return
+	}
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	for _, rqi := range rq.q {
+		rqi.reset()
+	}
+
+	rq.trace = nil
+	rq.passes = 0
+}
+
+/* ======================================================================== */
+
+// ResetTask returns the named task to the initial (UnRun) state. When
+// cascade is true, all tasks that (directly or indirectly) have an explicit
+// dependency on the named task are also reset.
+//
+// Tasks that depend on the named task by 'sense' (not an explicit dependency)
+// cannot be known, and are not reset.
+//
+// An error (ErrQNoTask) is returned if the name does not match a task.
+func (rq *InitQ) ResetTask(name string, cascade bool) (err error) {
+
+	if rq == nil {
+		return fmt.Errorf("%w: %s", ErrQNoTask, name)
+	}
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	if rq.item(name) == nil {
+		return fmt.Errorf("%w: %s", ErrQNoTask, name)
+	}
+
+	reset := []string{name}
+	if cascade {
+		reset = rq.dependants(name)
+	}
+
+	for _, rqi := range rq.q {
+		if slices.Contains(reset, rqi.name) {
+			rqi.reset()
+		}
+	}
+
+	return
+}
+
+/* ======================================================================== */
+
+// item returns the Q item with the matching label (or nil).
+func (rq *InitQ) item(name string) *initQItem {
+
+	for _, rqi := range rq.q {
+		if rqi.name == name {
+			return rqi
+		}
+	}
+
+	return nil
+}
+
+/* ======================================================================== */
+
+// dependants returns the named task, and all tasks that (directly or
+// indirectly) depend on it through explicit dependencies. The order is that
+// of discovery - the named task is first.
+func (rq *InitQ) dependants(name string) (names []string) {
+
+	names = []string{name}
+
+	// Walk the list as it grows. Each new name is checked for dependants.
+	for i := 0; i < len(names); i++ {
+		for _, rqi := range rq.q {
+			if slices.Contains(rqi.deps, names[i]) && !slices.Contains(names, rqi.name) {
+				names = append(names, rqi.name)
+			}
+		}
+	}
+
+	return
+}
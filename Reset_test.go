@@ -0,0 +1,120 @@
+package initq
+
+import (
+	"errors"
+	"testing"
+)
+
+/* ======================================================================== */
+
+func TestReset(t *testing.T) {
+
+	var rq *InitQ
+	var runs map[string]int
+
+	// counter returns a task function that counts its runs.
+	counter := func(name string, r ReqResult) QFunc {
+		return func() ReqResult {
+			runs[name]++
+			return r
+		}
+	}
+
+	// ----------
+	// A second Process does nothing. Reset runs everything again.
+
+	rq = NewInitQ()
+	runs = make(map[string]int)
+
+	rq.Add("one", counter("one", Satisfied))
+	rq.Add("two", counter("two", Satisfied), "one")
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if runs["one"] != 1 || len(rq.Trace()) != 0 {
+		t.Errorf("Expected the second Process to do nothing; one ran %d times", runs["one"])
+	}
+
+	rq.Reset()
+
+	if rq.States()["one"] != UnRun {
+		t.Errorf("Expected UnRun after Reset")
+	}
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if runs["one"] != 2 || runs["two"] != 2 {
+		t.Errorf("Expected both tasks to run again; got %v", runs)
+	}
+
+	// ----------
+	// ResetTask - with and without cascade.
+
+	rq.Add("three", counter("three", Satisfied), "two")
+	rq.Add("other", counter("other", Satisfied))
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if err := rq.ResetTask("two", false); err != nil {
+		t.Errorf("Unexpected ResetTask error - %s", err.Error())
+	}
+
+	states := rq.States()
+	if states["two"] != UnRun || states["three"] != Satisfied {
+		t.Errorf("Unexpected states after ResetTask %v", states)
+	}
+
+	if err := rq.ResetTask("one", true); err != nil {
+		t.Errorf("Unexpected ResetTask error - %s", err.Error())
+	}
+
+	states = rq.States()
+	if states["one"] != UnRun || states["two"] != UnRun || states["three"] != UnRun || states["other"] != Satisfied {
+		t.Errorf("Unexpected states after cascading ResetTask %v", states)
+	}
+
+	if err := rq.ResetTask("nope", true); !errors.Is(err, ErrQNoTask) {
+		t.Errorf("Expected ErrQNoTask; got %v", err)
+	}
+
+	// ----------
+	// Process again after a Stop (once the problem is fixed).
+
+	rq = NewInitQ()
+	runs = make(map[string]int)
+	fixed := false
+
+	rq.Add("one", counter("one", Satisfied))
+	rq.Add("flaky", func() ReqResult {
+		runs["flaky"]++
+		if !fixed {
+			return Stop
+		}
+		return Satisfied
+	})
+
+	if err := rq.Process(); err != ErrQStopped {
+		t.Errorf("Expected ErrQStopped; got %v", err)
+	}
+
+	fixed = true
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if runs["one"] != 1 || runs["flaky"] != 2 {
+		t.Errorf("Expected only the stopped task to run again; got %v", runs)
+	}
+
+}
@@ -0,0 +1,133 @@
+package initq
+
+import (
+	"context"
+	"slices"
+)
+
+/* ------------------------------------------------------------------------ */
+
+// Progress is a point-in-time snapshot of a processing Q.
+type Progress struct {
+	// Satisfied is the number of tasks Satisfied.
+	Satisfied int
+
+	// Total is the number of tasks in the Q.
+	Total int
+
+	// Pass is the number of passes completed.
+	Pass int
+
+	// Running are the tasks whose functions are currently running.
+	Running []string
+}
+
+/* ------------------------------------------------------------------------ */
+
+// Run is a handle to a Q that is processing in the background. It is
+// returned by Start and TryStart.
+type Run struct {
+	// rq is the Q being processed.
+	rq *InitQ
+
+	// cancel cancels the processing context (with a cause).
+	cancel context.CancelCauseFunc
+
+	// done is closed when processing ends.
+	done chan struct{}
+
+	// err is the result of processing. It is only valid once done is
+	// closed.
+	err error
+}
+
+/* ======================================================================== */
+
+// Start processes the Q (as ProcessContext does) in a new goroutine, and
+// returns immediately. The returned Run is used to wait for (or cancel)
+// processing. This allows (for example) an HTTP server to serve health
+// checks while the rest of the initialization proceeds.
+func (rq *InitQ) Start(ctx context.Context) *Run {
+	return rq.start(ctx, false)
+}
+
+/* ======================================================================== */
+
+// TryStart is Start with the error semantics of TryProcess.
+func (rq *InitQ) TryStart(ctx context.Context) *Run {
+	return rq.start(ctx, true)
+}
+
+/* ======================================================================== */
+
+// start is the common implementation of Start and TryStart.
+func (rq *InitQ) start(ctx context.Context, unsatIsError bool) (r *Run) {
+
+	r = new(Run)
+	r.rq = rq
+	r.done = make(chan struct{})
+
+	ctx, r.cancel = context.WithCancelCause(ctx)
+
+	go func() {
+		defer close(r.done)
+		defer r.cancel(nil)
+		r.err = rq.process(ctx, unsatIsError)
+	}()
+
+	return
+}
+
+/* ======================================================================== */
+
+// Wait blocks until processing ends, and returns the same error that
+// Process (or TryProcess) would have returned. A canceled run returns an
+// error wrapping ErrQCanceled.
+func (r *Run) Wait() error {
+	<-r.done
+	return r.err
+}
+
+/* ======================================================================== */
+
+// Done returns a channel that is closed when processing ends.
+func (r *Run) Done() <-chan struct{} {
+	return r.done
+}
+
+/* ======================================================================== */
+
+// Cancel stops processing before the next task is run. It does not wait
+// (use Wait for that).
+func (r *Run) Cancel() {
+	r.cancel(nil)
+}
+
+/* ======================================================================== */
+
+// Progress returns a snapshot of the progress of the run.
+func (r *Run) Progress() Progress {
+	return r.rq.Progress()
+}
+
+/* ======================================================================== */
+
+// Progress returns a snapshot of the progress of the Q. It is safe to call
+// while the Q is processing.
+func (rq *InitQ) Progress() (p Progress) {
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	p.Total = len(rq.q)
+	p.Pass = rq.passes
+	p.Running = slices.Clone(rq.running)
+
+	for _, rqi := range rq.q {
+		if rqi.state == Satisfied {
+			p.Satisfied++
+		}
+	}
+
+	return
+}
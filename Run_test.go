@@ -0,0 +1,123 @@
+package initq
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+/* ======================================================================== */
+
+func TestRun(t *testing.T) {
+
+	var rq *InitQ
+	var r *Run
+
+	// ----------
+	// A Q in the background - with progress while a task is running.
+
+	rq = NewInitQ()
+	gate := make(chan struct{})
+
+	rq.Add("cmdline", func() ReqResult { return Satisfied })
+	rq.Add("config", func() ReqResult {
+		<-gate
+		return Satisfied
+	})
+
+	r = rq.Start(context.Background())
+
+	for {
+		p := r.Progress()
+		if len(p.Running) == 1 && p.Running[0] == "config" {
+			if p.Satisfied != 1 || p.Total != 2 {
+				t.Errorf("Unexpected progress %+v", p)
+			}
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	select {
+	case <-r.Done():
+		t.Errorf("The run finished while a task was blocked")
+	default:
+	}
+
+	close(gate)
+
+	if err := r.Wait(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	if p := r.Progress(); p.Satisfied != 2 || len(p.Running) != 0 {
+		t.Errorf("Unexpected final progress %+v", p)
+	}
+
+	// ----------
+	// The error semantics are preserved.
+
+	rq = NewInitQ()
+	rq.Add("stopper", func() ReqResult { return Stop })
+
+	if err := rq.Start(context.Background()).Wait(); err != ErrQStopped {
+		t.Errorf("Expected ErrQStopped; got %v", err)
+	}
+
+	rq = NewInitQ()
+	rq.Add("never", func() ReqResult { return TryAgain })
+
+	if _, ok := rq.TryStart(context.Background()).Wait().(*QUnresolvable); !ok {
+		t.Errorf("Expected a *QUnresolvable error")
+	}
+
+	// ----------
+	// Cancel - the running task finishes, nothing else runs.
+
+	rq = NewInitQ()
+	gate = make(chan struct{})
+	ran := false
+
+	rq.Add("blocker", func() ReqResult {
+		<-gate
+		return Satisfied
+	})
+	rq.Add("after", func() ReqResult {
+		ran = true
+		return Satisfied
+	}, "blocker")
+
+	r = rq.Start(context.Background())
+
+	for len(r.Progress().Running) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	r.Cancel()
+	close(gate)
+
+	err := r.Wait()
+	if !errors.Is(err, ErrQCanceled) || !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a canceled error; got %v", err)
+	}
+
+	if ran {
+		t.Errorf("A task ran after the Q was canceled")
+	}
+
+	// ----------
+	// A timed out context (synchronous).
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+
+	rq = NewInitQ()
+	rq.Add("one", func() ReqResult { return Satisfied })
+
+	if err := rq.ProcessContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a deadline error; got %v", err)
+	}
+
+}
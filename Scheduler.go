@@ -0,0 +1,60 @@
+package initq
+
+/* ------------------------------------------------------------------------ */
+
+// Scheduler determines when a task that returned TryAgain is called again.
+type Scheduler int
+
+/* ------------------------------------------------------------------------ */
+
+const (
+	// SchedulePasses calls every task that is not Satisfied on every pass
+	// (until the pass budget is exhausted). This is the default. It suits
+	// tasks that poll for something outside of the Q.
+	SchedulePasses Scheduler = iota
+
+	// ScheduleEvents only calls a task that returned TryAgain again once
+	// some other task has been Satisfied (since it was last called). The Q
+	// is unresolved as soon as a pass makes no progress - rather than when
+	// the pass budget is exhausted. A task that polls for something outside
+	// of the Q (and is the only thing not Satisfied) is not called again.
+	ScheduleEvents
+)
+
+/* ======================================================================== */
+
+// SetScheduler sets the scheduler used by the next process run. It must not
+// be called while the Q is processing.
+func (rq *InitQ) SetScheduler(s Scheduler) {
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	rq.scheduler = s
+}
+
+/* ======================================================================== */
+
+// stale reports if calling the task function again is pointless - because
+// nothing was Satisfied since its last call (that returned TryAgain). This
+// is only the case for the ScheduleEvents scheduler. It must be called with
+// the lock held.
+func (rq *InitQ) stale(rqi *initQItem) bool {
+	return rq.scheduler == ScheduleEvents && rqi.state == TryAgain && rqi.heard == rq.progress
+}
+
+/* ======================================================================== */
+
+// Skipped returns the number of task function calls that the scheduler
+// skipped (see ScheduleEvents) in the last process run.
+func (rq *InitQ) Skipped() int {
+
+	if rq == nil {
+		return 0
+	}
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	return rq.skipped
+}
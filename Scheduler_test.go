@@ -0,0 +1,106 @@
+package initq
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+/* ======================================================================== */
+
+func TestScheduler(t *testing.T) {
+
+	var sim *Simulation
+	var sr SimResult
+
+	// build is a Q with many tasks, and one (last) task that never
+	// satisfies.
+	build := func(s Scheduler) *Simulation {
+
+		sim := NewSimulation()
+		sim.SetScheduler(s)
+
+		for i := range 99 {
+			sim.Add(fmt.Sprintf("task%d", i), Always(Satisfied))
+		}
+		sim.Add("never", Always(TryAgain))
+
+		return sim
+	}
+
+	// ----------
+	// The passes scheduler calls the unsatisfied task on every pass.
+
+	sr = build(SchedulePasses).Run()
+
+	if _, ok := sr.Err.(*QUnresolvable); !ok {
+		t.Errorf("Expected a *QUnresolvable error; got %v", sr.Err)
+	}
+
+	if sr.Passes != 101 || len(sr.Trace) != 200 || sr.Skipped != 0 {
+		t.Errorf("Expected 101 passes / 200 calls; got %d / %d", sr.Passes, len(sr.Trace))
+	}
+
+	// ----------
+	// The events scheduler stops once nothing changes.
+
+	sr = build(ScheduleEvents).Run()
+
+	if _, ok := sr.Err.(*QUnresolvable); !ok {
+		t.Errorf("Expected a *QUnresolvable error; got %v", sr.Err)
+	}
+
+	if sr.Passes != 2 || len(sr.Trace) != 100 || sr.Skipped != 1 {
+		t.Errorf("Expected 2 passes / 100 calls / 1 skip; got %d / %d / %d", sr.Passes, len(sr.Trace), sr.Skipped)
+	}
+
+	// ----------
+	// A resolvable Q finishes the same way under both.
+
+	for _, s := range []Scheduler{SchedulePasses, ScheduleEvents} {
+
+		sim = NewSimulation()
+		sim.SetScheduler(s)
+
+		sim.Add("server", SatisfiedAfter("dbconn"))
+		sim.Add("dbconn", SatisfiedAfter("config"))
+		sim.Add("report", Always(Satisfied), "server")
+		sim.Add("config", SatisfiedAfter("cmdline"))
+		sim.Add("cmdline", Always(Satisfied))
+
+		sr = sim.Run()
+
+		if sr.Err != nil {
+			t.Errorf("Simulated Q did not finish - %s", sr.Err.Error())
+		}
+
+		order := SatisfiedOrder(sr.Trace)
+		if !slices.Equal(order, []string{"cmdline", "config", "dbconn", "server", "report"}) {
+			t.Errorf("Unexpected satisfied order %v", order)
+		}
+	}
+
+	// ----------
+	// Explicit dependencies, and tasks that need a second call, under the
+	// events scheduler.
+
+	sim = NewSimulation()
+	sim.SetScheduler(ScheduleEvents)
+
+	sim.Add("client", SatisfiedAfter("server"))
+	sim.Add("server", Always(Satisfied), "setup")
+	sim.Add("setup", Always(Satisfied))
+	sim.Add("poll", Sequence(TryAgain, Satisfied))
+
+	sr = sim.Run()
+
+	if sr.Err != nil {
+		t.Errorf("Simulated Q did not finish - %s", sr.Err.Error())
+	}
+
+	// client is called in each pass (as something was Satisfied in each).
+	if sr.Passes != 3 || len(sr.Trace) != 7 || sr.Skipped != 0 {
+		t.Errorf("Expected 3 passes / 7 calls; got %d / %d", sr.Passes, len(sr.Trace))
+	}
+
+}
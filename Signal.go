@@ -0,0 +1,90 @@
+package initq
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+/* ------------------------------------------------------------------------ */
+
+// Interrupted is the cause of a run that was canceled by a signal (see
+// WaitSignals). It is found in the returned error with errors.As.
+type Interrupted struct {
+	// Signal is the signal that was received.
+	Signal os.Signal
+
+	// Expired is true when the running tasks did not finish within the
+	// grace period. Processing is still ending in the background. The
+	// tasks that were Satisfied have been compensated, but those that were
+	// still running are only compensated (if they are Satisfied) once
+	// processing ends.
+	Expired bool
+}
+
+/* ======================================================================== */
+
+// Error satisfies the error interface.
+func (intr *Interrupted) Error() (msg string) {
+
+	msg = fmt.Sprintf("interrupted by %s", intr.Signal.String())
+	if intr.Expired {
+		msg += " (grace period expired)"
+	}
+
+	return
+}
+
+/* ======================================================================== */
+
+// WaitSignals is Wait that cancels the run when a signal is received. The
+// signals are SIGINT and SIGTERM unless others are given. When a signal is
+// received:
+//
+//   - No further tasks are started.
+//   - Running tasks are given the grace period to finish. (Task.Context is
+//     canceled, so tasks that take a Task handle may finish early.)
+//   - Satisfied tasks are compensated (see SetCompensation).
+//
+// The error wraps ErrQCanceled and an *Interrupted. If the running tasks do
+// not finish within the grace period, the Satisfied tasks are compensated
+// and WaitSignals returns without waiting for the running tasks (and the
+// Interrupted is Expired).
+func (r *Run) WaitSignals(grace time.Duration, sigs ...os.Signal) error {
+
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	defer signal.Stop(ch)
+
+	var sig os.Signal
+
+	select {
+	case <-r.done:
+		return r.err
+	case sig = <-ch:
+	}
+
+	r.cancel(&Interrupted{Signal: sig})
+
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+
+	select {
+	case <-r.done:
+		return r.err
+	case <-timer.C:
+	}
+
+	// The caller is likely to exit. The Satisfied tasks are compensated now
+	// - while the running tasks are still running.
+	r.rq.mu.Lock()
+	defer r.rq.mu.Unlock()
+
+	return r.rq.compensate(fmt.Errorf("%w: %w", ErrQCanceled, &Interrupted{Signal: sig, Expired: true}))
+}
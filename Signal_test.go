@@ -0,0 +1,126 @@
+//go:build unix
+
+package initq
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+)
+
+/* ======================================================================== */
+
+func TestWaitSignals(t *testing.T) {
+
+	// The test process would be terminated by a signal that arrives before
+	// WaitSignals is listening. This keeps it alive.
+	guard := make(chan os.Signal, 16)
+	signal.Notify(guard, syscall.SIGINT)
+	defer signal.Stop(guard)
+
+	// interrupt sends SIGINT to the test process (repeatedly) once the task
+	// has started, until the run is done.
+	interrupt := func(r *Run, started <-chan struct{}) {
+		<-started
+		for {
+			select {
+			case <-r.Done():
+				return
+			case <-time.After(10 * time.Millisecond):
+				syscall.Kill(os.Getpid(), syscall.SIGINT)
+			}
+		}
+	}
+
+	var rq *InitQ
+	var undone []string
+
+	// ----------
+	// A task that honours the context finishes early, and the Satisfied
+	// tasks are compensated.
+
+	rq = NewInitQ()
+	started := make(chan struct{})
+
+	rq.Add("tmpdir", func() ReqResult { return Satisfied })
+	rq.SetCompensation("tmpdir", func() error { undone = append(undone, "tmpdir"); return nil })
+	rq.AddTask("slow", func(t *Task) ReqResult {
+		close(started)
+		<-t.Context().Done()
+		return TryAgain
+	}, "tmpdir")
+	rq.Add("never", func() ReqResult { t.Errorf("A task was started after the signal"); return Satisfied }, "slow")
+
+	r := rq.Start(context.Background())
+	go interrupt(r, started)
+
+	err := r.WaitSignals(5 * time.Second)
+
+	var intr *Interrupted
+	if !errors.Is(err, ErrQCanceled) || !errors.As(err, &intr) || intr.Expired || intr.Signal != syscall.SIGINT {
+		t.Errorf("Expected an interrupted error; got %v", err)
+	}
+
+	if !slices.Equal(undone, []string{"tmpdir"}) {
+		t.Errorf("Expected the compensation to run; got %v", undone)
+	}
+
+	// ----------
+	// A task that does not finish within the grace period.
+
+	// The Satisfied tasks are compensated before WaitSignals returns
+	// (while the stuck task is still running), and only once.
+
+	rq = NewInitQ()
+	started = make(chan struct{})
+	release := make(chan struct{})
+	undone = nil
+
+	rq.Add("tmpdir", func() ReqResult { return Satisfied })
+	rq.SetCompensation("tmpdir", func() error { undone = append(undone, "tmpdir"); return nil })
+	rq.Add("stuck", func() ReqResult {
+		close(started)
+		<-release
+		return Satisfied
+	}, "tmpdir")
+	rq.Add("after", func() ReqResult { return Satisfied }, "stuck")
+
+	r = rq.Start(context.Background())
+	go interrupt(r, started)
+
+	err = r.WaitSignals(20 * time.Millisecond)
+
+	if !errors.As(err, &intr) || !intr.Expired || !errors.Is(err, ErrQCanceled) {
+		t.Errorf("Expected an expired grace period; got %v", err)
+	}
+
+	if !slices.Equal(undone, []string{"tmpdir"}) {
+		t.Errorf("Expected the compensation to run before returning; got %v", undone)
+	}
+
+	close(release)
+
+	if err := r.Wait(); !errors.Is(err, ErrQCanceled) {
+		t.Errorf("Expected ErrQCanceled; got %v", err)
+	}
+
+	if !slices.Equal(undone, []string{"tmpdir"}) {
+		t.Errorf("Expected a single compensation; got %v", undone)
+	}
+
+	// ----------
+	// Without a signal, WaitSignals is Wait.
+
+	rq = NewInitQ()
+	rq.Add("one", func() ReqResult { return Satisfied })
+
+	if err := rq.Start(context.Background()).WaitSignals(time.Second); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+}
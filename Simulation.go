@@ -0,0 +1,179 @@
+package initq
+
+/* ------------------------------------------------------------------------ */
+
+// Script is a scripted behaviour that stands in for a task function in a
+// Simulation. It is called with the Simulation (to check on other tasks)
+// and the (1-based) count of attempts of this task - including this one.
+type Script func(sim *Simulation, attempt int) ReqResult
+
+/* ------------------------------------------------------------------------ */
+
+// Simulation is a dry-run of a Q. Each task is replaced by a Script so that
+// the shape of a Q (the order tasks are attempted in and the passes needed)
+// can be evaluated without touching real resources.
+//
+// A Simulation is processed with the same rules as a real InitQ - including
+// the log.Fatal() assertions on a misdefined Q.
+type Simulation struct {
+	// rq is the (real) InitQ that runs the scripts.
+	rq *InitQ
+
+	// attempts is the count of attempts per task.
+	attempts map[string]int
+}
+
+/* ------------------------------------------------------------------------ */
+
+// SimResult is the outcome of a Simulation run.
+type SimResult struct {
+	// Trace is each task invocation, in order.
+	Trace []Attempt
+
+	// Passes is the number of passes of the Q that were required.
+	Passes int
+
+	// Bound is the maximum number of passes the Q was allowed.
+	Bound int
+
+	// Skipped is the number of calls skipped by the scheduler.
+	Skipped int
+
+	// Err is the error from processing the Q. It is nil if the Q was
+	// satisfied.
+	Err error
+}
+
+/* ======================================================================== */
+
+// NewSimulation creates a new / empty Simulation.
+func NewSimulation() (sim *Simulation) {
+
+	sim = new(Simulation)
+	sim.rq = NewInitQ()
+	sim.attempts = make(map[string]int)
+
+	return
+}
+
+/* ======================================================================== */
+
+// Add puts a scripted task on the simulated Q. The name and dependencies are
+// the same as the InitQ Add method.
+func (sim *Simulation) Add(name string, sc Script, deps ...string) {
+
+	var f QFunc
+	if sc != nil {
+		f = func() ReqResult {
+			sim.attempts[name]++
+			return sc(sim, sim.attempts[name])
+		}
+	}
+
+	sim.rq.Add(name, f, deps...)
+}
+
+/* ======================================================================== */
+
+// SetScheduler sets the scheduler of the simulated Q. This allows the cost
+// (in calls) of each scheduler to be compared.
+func (sim *Simulation) SetScheduler(s Scheduler) {
+	sim.rq.SetScheduler(s)
+}
+
+/* ======================================================================== */
+
+// SetMaxPasses sets the pass budget of the simulated Q (see
+// InitQ.SetMaxPasses). The budget is reported as the Bound of the result.
+func (sim *Simulation) SetMaxPasses(max int) {
+	sim.rq.SetMaxPasses(max)
+}
+
+/* ======================================================================== */
+
+// SetStallPasses enables stall detection in the simulated Q (see
+// InitQ.SetStallPasses).
+func (sim *Simulation) SetStallPasses(n int) {
+	sim.rq.SetStallPasses(n)
+}
+
+/* ======================================================================== */
+
+// Satisfied reports if the named task has been satisfied (so far) in the
+// simulation. It is intended for use by Scripts.
+func (sim *Simulation) Satisfied(name string) bool {
+
+	sim.rq.mu.Lock()
+	defer sim.rq.mu.Unlock()
+
+	return sim.rq.satisfied(name)
+}
+
+/* ======================================================================== */
+
+// Run processes the simulated Q (as TryProcess does) and returns the trace.
+// A Simulation is only intended to be run once.
+func (sim *Simulation) Run() (sr SimResult) {
+
+	sr.Err = sim.rq.TryProcess()
+	sr.Trace = sim.rq.Trace()
+	sr.Passes = sim.rq.Passes()
+	sr.Bound = sim.rq.passBudget()
+	sr.Skipped = sim.rq.Skipped()
+
+	return
+}
+
+/* ======================================================================== */
+
+// Always is a Script that returns the same result on every attempt.
+func Always(r ReqResult) Script {
+	return func(*Simulation, int) ReqResult {
+		return r
+	}
+}
+
+/* ======================================================================== */
+
+// SatisfiedAfter is a Script that returns TryAgain until all of the named
+// tasks are Satisfied, and then returns Satisfied. This is the typical
+// 'sense' style task.
+func SatisfiedAfter(names ...string) Script {
+	return func(sim *Simulation, _ int) ReqResult {
+		for _, n := range names {
+			if !sim.Satisfied(n) {
+				return TryAgain
+			}
+		}
+		return Satisfied
+	}
+}
+
+/* ======================================================================== */
+
+// SatisfiedOnAttempt is a Script that returns TryAgain until the nth attempt
+// and then returns Satisfied.
+func SatisfiedOnAttempt(n int) Script {
+	return func(_ *Simulation, attempt int) ReqResult {
+		if attempt < n {
+			return TryAgain
+		}
+		return Satisfied
+	}
+}
+
+/* ======================================================================== */
+
+// Sequence is a Script that returns each result in turn. The last result is
+// repeated once the sequence is exhausted.
+func Sequence(results ...ReqResult) Script {
+	return func(_ *Simulation, attempt int) ReqResult {
+		if len(results) == 0 {
+			return TryAgain
+		}
+		if attempt > len(results) {
+			return results[len(results)-1]
+		}
+		return results[attempt-1]
+	}
+}
@@ -0,0 +1,126 @@
+package initq
+
+import (
+	"slices"
+	"testing"
+)
+
+/* ======================================================================== */
+
+func TestSimulation(t *testing.T) {
+
+	var sim *Simulation
+	var sr SimResult
+
+	// ----------
+	// Worst case (backwards) ordering of sense-style tasks.
+
+	sim = NewSimulation()
+
+	sim.Add("server", SatisfiedAfter("dbconn"))
+	sim.Add("dbconn", SatisfiedAfter("config"))
+	sim.Add("config", SatisfiedAfter("cmdline"))
+	sim.Add("cmdline", Always(Satisfied))
+
+	sr = sim.Run()
+
+	if sr.Err != nil {
+		t.Errorf("Simulated Q did not finish - %s", sr.Err.Error())
+	}
+
+	if sr.Passes != 4 {
+		t.Errorf("Expected 4 passes; got %d", sr.Passes)
+	}
+
+	if sr.Bound != 5 {
+		t.Errorf("Expected a bound of 5 passes; got %d", sr.Bound)
+	}
+
+	// 4 + 3 + 2 + 1 invocations.
+	if len(sr.Trace) != 10 {
+		t.Errorf("Expected 10 invocations; got %d", len(sr.Trace))
+	}
+
+	order := SatisfiedOrder(sr.Trace)
+	if !slices.Equal(order, []string{"cmdline", "config", "dbconn", "server"}) {
+		t.Errorf("Unexpected satisfied order %v", order)
+	}
+
+	// ----------
+	// Best case ordering of the same Q.
+
+	sim = NewSimulation()
+
+	sim.Add("cmdline", Always(Satisfied))
+	sim.Add("config", SatisfiedAfter("cmdline"))
+	sim.Add("dbconn", SatisfiedAfter("config"))
+	sim.Add("server", SatisfiedAfter("dbconn"))
+
+	sr = sim.Run()
+
+	if sr.Passes != 1 || len(sr.Trace) != 4 {
+		t.Errorf("Expected 1 pass / 4 invocations; got %d / %d", sr.Passes, len(sr.Trace))
+	}
+
+	// ----------
+	// Explicit dependencies skip invocation - and mixed scripts.
+
+	sim = NewSimulation()
+
+	sim.Add("poll", SatisfiedOnAttempt(3))
+	sim.Add("settime", Sequence(TryAgain, Satisfied))
+	sim.Add("sched", Always(Satisfied), "settime", "poll")
+
+	sr = sim.Run()
+
+	if sr.Err != nil {
+		t.Errorf("Simulated Q did not finish - %s", sr.Err.Error())
+	}
+
+	if sr.Passes != 3 {
+		t.Errorf("Expected 3 passes; got %d", sr.Passes)
+	}
+
+	for _, a := range sr.Trace {
+		if a.Task == "sched" && a.Pass != 3 {
+			t.Errorf("The sched task ran before its dependencies (pass %d)", a.Pass)
+		}
+	}
+
+	// ----------
+	// A Q that can never finish.
+
+	sim = NewSimulation()
+
+	sim.Add("one", Always(Satisfied))
+	sim.Add("never", Always(TryAgain))
+
+	sr = sim.Run()
+
+	if _, ok := sr.Err.(*QUnresolvable); !ok {
+		t.Errorf("Expected a *QUnresolvable error; got %v", sr.Err)
+	}
+
+	if sr.Passes != sr.Bound {
+		t.Errorf("Expected the bound (%d) to be exhausted; got %d", sr.Bound, sr.Passes)
+	}
+
+	// ----------
+	// A stopped Q.
+
+	sim = NewSimulation()
+
+	sim.Add("one", Always(Satisfied))
+	sim.Add("stopper", Sequence(Stop))
+
+	sr = sim.Run()
+
+	if sr.Err != ErrQStopped {
+		t.Errorf("Expected ErrQStopped; got %v", sr.Err)
+	}
+
+	if sr.Trace[len(sr.Trace)-1].Result.String() != "stop" {
+		t.Errorf("Expected the trace to end with a stop")
+	}
+
+}
@@ -0,0 +1,60 @@
+package initq
+
+import "slices"
+
+/* ------------------------------------------------------------------------ */
+
+// TaskStatus is a point-in-time snapshot of a single task in the Q.
+type TaskStatus struct {
+	// Name is the task label.
+	Name string `json:"name"`
+
+	// State is the current state of the task.
+	State ReqResult `json:"state"`
+
+	// Deps are the explicit dependencies of the task.
+	Deps []string `json:"deps,omitempty"`
+
+	// Invocations is the number of times the task function was called.
+	Invocations int `json:"invocations"`
+
+	// WaitingOn and Reason are what the task said it was waiting for (when
+	// it last returned TryAgain).
+	WaitingOn []string `json:"waiting_on,omitempty"`
+	Reason    string   `json:"reason,omitempty"`
+}
+
+/* ======================================================================== */
+
+// Status returns a snapshot of every task in the Q (in the order they were
+// added). It is safe to call while the Q is processing.
+func (rq *InitQ) Status() (tasks []TaskStatus) {
+
+	if rq == nil {
+		return nil
+	}
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	tasks = make([]TaskStatus, 0, len(rq.q))
+	for _, rqi := range rq.q {
+		tasks = append(tasks, rqi.status())
+	}
+
+	return
+}
+
+/* ======================================================================== */
+
+// status returns a snapshot of the item.
+func (rqi *initQItem) status() TaskStatus {
+	return TaskStatus{
+		Name:        rqi.name,
+		State:       rqi.state,
+		Deps:        slices.Clone(rqi.deps),
+		Invocations: rqi.invocations,
+		WaitingOn:   slices.Clone(rqi.waits),
+		Reason:      rqi.reason,
+	}
+}
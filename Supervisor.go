@@ -0,0 +1,281 @@
+package initq
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+/* ------------------------------------------------------------------------ */
+
+// Probe is a health / liveness check for a (Satisfied) task. It returns nil
+// when the component initialized by the task is healthy.
+type Probe func() error
+
+/* ------------------------------------------------------------------------ */
+
+// Strategy determines which tasks are restarted when a probe fails.
+type Strategy int
+
+/* ------------------------------------------------------------------------ */
+
+const (
+	// OneForOne restarts the failed task and the tasks that (directly or
+	// indirectly) have an explicit dependency on it.
+	OneForOne Strategy = iota
+
+	// RestForOne restarts the failed task and every task that was Satisfied
+	// after it - in addition to the OneForOne set. This covers tasks that
+	// depend on the failed task by 'sense'.
+	RestForOne
+)
+
+/* ------------------------------------------------------------------------ */
+
+// Restart is a record of a single restart of a task.
+type Restart struct {
+	// Time is when the restart happened.
+	Time time.Time
+
+	// Cause is the task whose probe failed. It is the same as the task name
+	// when the task itself failed.
+	Cause string
+
+	// Err is the error returned by the probe.
+	Err error
+}
+
+/* ------------------------------------------------------------------------ */
+
+// Supervisor watches the (Satisfied) tasks of a processed Q, and restarts
+// them when their probes fail. A restart tears down the failed task and its
+// dependants (in reverse order of completion) and then re-processes the Q.
+//
+// The Q must have been processed (to completion) before the Supervisor is
+// run.
+type Supervisor struct {
+	// rq is the supervised Q.
+	rq *InitQ
+
+	// strategy determines what is restarted.
+	strategy Strategy
+
+	// interval is the time between probe rounds (in Run).
+	interval time.Duration
+
+	// maxRestarts is the number of restarts (per task) allowed within the
+	// window. Zero is unlimited.
+	maxRestarts int
+	window      time.Duration
+
+	// probes and teardowns are per task (by name).
+	probes    map[string]Probe
+	teardowns map[string]func()
+
+	// history is the restart history per task.
+	history map[string][]Restart
+
+	// now is the clock. It is replaceable for test.
+	now func() time.Time
+
+	// mu protects probes, teardowns, and history (that may be used while
+	// Run is active).
+	mu sync.Mutex
+}
+
+/* ======================================================================== */
+
+// NewSupervisor creates a Supervisor for the Q. By default, probes are run
+// every second, and there is no restart budget.
+func NewSupervisor(rq *InitQ, strategy Strategy) (sup *Supervisor) {
+
+	sup = new(Supervisor)
+
+	sup.rq = rq
+	sup.strategy = strategy
+	sup.interval = time.Second
+	sup.probes = make(map[string]Probe)
+	sup.teardowns = make(map[string]func())
+	sup.history = make(map[string][]Restart)
+	sup.now = time.Now
+
+	return
+}
+
+/* ======================================================================== */
+
+// Watch registers a probe (and an optional teardown function) for the named
+// task. The teardown is called before the task is restarted - whether it is
+// restarted because its own probe failed or as a dependant of another task.
+//
+// An error (ErrQNoTask) is returned if the name does not match a task.
+func (sup *Supervisor) Watch(name string, probe Probe, teardown func()) (err error) {
+
+	sup.rq.mu.Lock()
+	rqi := sup.rq.item(name)
+	sup.rq.mu.Unlock()
+
+	if rqi == nil {
+		return fmt.Errorf("%w: %s", ErrQNoTask, name)
+	}
+
+	sup.mu.Lock()
+	defer sup.mu.Unlock()
+
+	if probe != nil {
+		sup.probes[name] = probe
+	}
+
+	if teardown != nil {
+		sup.teardowns[name] = teardown
+	}
+
+	return
+}
+
+/* ======================================================================== */
+
+// SetInterval sets the time between probe rounds.
+func (sup *Supervisor) SetInterval(d time.Duration) {
+	sup.interval = d
+}
+
+/* ======================================================================== */
+
+// SetBudget limits restarts of any one task to max within the (sliding)
+// window. When the budget is exceeded, Run returns ErrQRestartBudget.
+func (sup *Supervisor) SetBudget(max int, window time.Duration) {
+	sup.maxRestarts = max
+	sup.window = window
+}
+
+/* ======================================================================== */
+
+// Run probes the watched tasks (every interval) until the context is done,
+// a restart fails, or the restart budget is exceeded.
+func (sup *Supervisor) Run(ctx context.Context) (err error) {
+
+	ticker := time.NewTicker(sup.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err = sup.Check(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+/* ======================================================================== */
+
+// Check runs a single round of probes, and restarts (in order of completion)
+// any tasks that fail. Run calls this on each interval. It is exported for
+// callers that want to drive the schedule themselves.
+func (sup *Supervisor) Check() (err error) {
+
+	sup.rq.mu.Lock()
+	completed := sup.rq.completed()
+	sup.rq.mu.Unlock()
+
+	for _, name := range completed {
+
+		sup.mu.Lock()
+		probe, ok := sup.probes[name]
+		sup.mu.Unlock()
+
+		if !ok {
+			continue
+		}
+
+		// A task restarted (as a dependant) earlier in this round is probed
+		// in its new state.
+		if perr := probe(); perr != nil {
+			if err = sup.restart(name, perr); err != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
+
+/* ======================================================================== */
+
+// History returns the restart history of the named task.
+func (sup *Supervisor) History(name string) []Restart {
+
+	sup.mu.Lock()
+	defer sup.mu.Unlock()
+
+	return slices.Clone(sup.history[name])
+}
+
+/* ======================================================================== */
+
+// restart tears down (and re-processes) the failed task and its dependants.
+func (sup *Supervisor) restart(failed string, cause error) (err error) {
+
+	now := sup.now()
+
+	// Check the budget (of the failed task) first. Only restarts caused by
+	// the task itself count - not those as a dependant of another task.
+	if sup.maxRestarts > 0 {
+		recent := 0
+		for _, r := range sup.History(failed) {
+			if r.Cause == failed && now.Sub(r.Time) < sup.window {
+				recent++
+			}
+		}
+		if recent >= sup.maxRestarts {
+			return fmt.Errorf("%w: %s (%s)", ErrQRestartBudget, failed, cause.Error())
+		}
+	}
+
+	// Determine the restart set. The order of completion is used so that
+	// tear down happens in reverse.
+	sup.rq.mu.Lock()
+	completed := sup.rq.completed()
+	restart := sup.rq.dependants(failed)
+	sup.rq.mu.Unlock()
+
+	if sup.strategy == RestForOne {
+		if i := slices.Index(completed, failed); i >= 0 {
+			for _, name := range completed[i:] {
+				if !slices.Contains(restart, name) {
+					restart = append(restart, name)
+				}
+			}
+		}
+	}
+
+	for i := len(completed) - 1; i >= 0; i-- {
+
+		name := completed[i]
+		if !slices.Contains(restart, name) {
+			continue
+		}
+
+		sup.mu.Lock()
+		td, ok := sup.teardowns[name]
+		sup.mu.Unlock()
+
+		if ok {
+			td()
+		}
+
+		// The name is known to be valid.
+		_ = sup.rq.ResetTask(name, false)
+
+		sup.mu.Lock()
+		sup.history[name] = append(sup.history[name], Restart{Time: now, Cause: failed, Err: cause})
+		sup.mu.Unlock()
+	}
+
+	return sup.rq.TryProcess()
+}
@@ -0,0 +1,191 @@
+package initq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+/* ======================================================================== */
+
+// component is a fake (restartable) component for supervisor tests.
+type component struct {
+	up     bool
+	fail   bool
+	starts int
+}
+
+func (c *component) start() ReqResult {
+	c.up = true
+	c.fail = false
+	c.starts++
+	return Satisfied
+}
+
+func (c *component) probe() error {
+	if c.fail {
+		return fmt.Errorf("component failed")
+	}
+	return nil
+}
+
+/* ======================================================================== */
+
+func TestSupervisor(t *testing.T) {
+
+	var rq *InitQ
+	var sup *Supervisor
+	var bus, consumer, other *component
+	var torn []string
+
+	// build creates (and processes) the Q used in each case.
+	build := func(strategy Strategy) {
+
+		rq = NewInitQ()
+		bus, consumer, other = new(component), new(component), new(component)
+		torn = nil
+
+		rq.Add("bus", bus.start)
+		rq.Add("consumer", consumer.start, "bus")
+		rq.Add("other", other.start)
+
+		if err := rq.Process(); err != nil {
+			t.Fatalf("Q did not finish - %s", err.Error())
+		}
+
+		sup = NewSupervisor(rq, strategy)
+
+		for name, c := range map[string]*component{"bus": bus, "consumer": consumer, "other": other} {
+			if err := sup.Watch(name, c.probe, func() { torn = append(torn, name) }); err != nil {
+				t.Fatalf("Unexpected Watch error - %s", err.Error())
+			}
+		}
+	}
+
+	// ----------
+	// Nothing fails - nothing restarts.
+
+	build(OneForOne)
+
+	if err := sup.Check(); err != nil {
+		t.Errorf("Unexpected Check error - %s", err.Error())
+	}
+
+	if bus.starts != 1 || len(sup.History("bus")) != 0 {
+		t.Errorf("Unexpected restart of a healthy task")
+	}
+
+	// ----------
+	// One-for-one: the bus and its explicit dependant restart (in reverse).
+
+	build(OneForOne)
+	bus.fail = true
+
+	if err := sup.Check(); err != nil {
+		t.Errorf("Unexpected Check error - %s", err.Error())
+	}
+
+	if bus.starts != 2 || consumer.starts != 2 || other.starts != 1 {
+		t.Errorf("Unexpected restarts (bus %d, consumer %d, other %d)", bus.starts, consumer.starts, other.starts)
+	}
+
+	if !slices.Equal(torn, []string{"consumer", "bus"}) {
+		t.Errorf("Unexpected teardown order %v", torn)
+	}
+
+	if h := sup.History("consumer"); len(h) != 1 || h[0].Cause != "bus" {
+		t.Errorf("Unexpected consumer history %v", h)
+	}
+
+	// ----------
+	// Rest-for-one: everything completed after the bus restarts.
+
+	build(RestForOne)
+	bus.fail = true
+
+	if err := sup.Check(); err != nil {
+		t.Errorf("Unexpected Check error - %s", err.Error())
+	}
+
+	if other.starts != 2 {
+		t.Errorf("Expected other to restart; started %d times", other.starts)
+	}
+
+	if !slices.Equal(torn, []string{"other", "consumer", "bus"}) {
+		t.Errorf("Unexpected teardown order %v", torn)
+	}
+
+	// ----------
+	// The restart budget.
+
+	build(OneForOne)
+	sup.SetBudget(2, time.Minute)
+
+	for range 2 {
+		consumer.fail = true
+		if err := sup.Check(); err != nil {
+			t.Errorf("Unexpected Check error - %s", err.Error())
+		}
+	}
+
+	consumer.fail = true
+	if err := sup.Check(); !errors.Is(err, ErrQRestartBudget) {
+		t.Errorf("Expected ErrQRestartBudget; got %v", err)
+	}
+
+	// Outside of the window the budget is restored.
+	sup.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if err := sup.Check(); err != nil {
+		t.Errorf("Unexpected Check error - %s", err.Error())
+	}
+
+	// Restarts as a dependant (of the bus) do not count against the
+	// budget of the consumer.
+	build(OneForOne)
+	sup.SetBudget(2, time.Minute)
+
+	for range 2 {
+		bus.fail = true
+		if err := sup.Check(); err != nil {
+			t.Errorf("Unexpected Check error - %s", err.Error())
+		}
+	}
+
+	consumer.fail = true
+	if err := sup.Check(); err != nil {
+		t.Errorf("Unexpected Check error - %s", err.Error())
+	}
+
+	if consumer.starts != 4 {
+		t.Errorf("Expected the consumer to restart; started %d times", consumer.starts)
+	}
+
+	// ----------
+	// Run (until cancelled) and Watch misuse.
+
+	build(OneForOne)
+	sup.SetInterval(time.Millisecond)
+	other.fail = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Watch may be called while Run is active.
+	go sup.Watch("bus", bus.probe, nil)
+
+	if err := sup.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline; got %v", err)
+	}
+
+	if other.starts < 2 {
+		t.Errorf("Expected Run to restart other")
+	}
+
+	if err := sup.Watch("nope", nil, nil); !errors.Is(err, ErrQNoTask) {
+		t.Errorf("Expected ErrQNoTask; got %v", err)
+	}
+
+}
@@ -0,0 +1,99 @@
+package initq
+
+import (
+	"context"
+	"slices"
+)
+
+/* ------------------------------------------------------------------------ */
+
+// TaskFunc is the prototype for an InitQ requirement that takes a Task
+// handle. The handle is used to tell the Q *why* the task could not yet be
+// Satisfied.
+type TaskFunc func(t *Task) ReqResult
+
+/* ------------------------------------------------------------------------ */
+
+// Task is the handle passed to a TaskFunc. It is only valid for the duration
+// of the call.
+type Task struct {
+	// name is the task label.
+	name string
+
+	// ctx is the context of the process run.
+	ctx context.Context
+
+	// waits are the tasks this task is waiting on (from WaitingOn).
+	waits []string
+
+	// reason is a free-form description of what the task is waiting for.
+	reason string
+
+	// err is the error of a failed task (from Fail).
+	err error
+}
+
+/* ======================================================================== */
+
+// newTask creates the handle for a single invocation of the named task.
+func newTask(ctx context.Context, name string) (t *Task) {
+
+	t = new(Task)
+	t.ctx = ctx
+	t.name = name
+
+	return
+}
+
+/* ======================================================================== */
+
+// Name returns the label of the task.
+func (t *Task) Name() string {
+	return t.name
+}
+
+/* ======================================================================== */
+
+// Context returns the context of the process run (see ProcessContext). A
+// long running task may use it to abandon its work when processing is
+// canceled - such as by a signal (see Run.WaitSignals).
+func (t *Task) Context() context.Context {
+	return t.ctx
+}
+
+/* ======================================================================== */
+
+// WaitingOn declares the (sense) dependencies that the task is waiting on,
+// and returns TryAgain. The task function is not called again until all of
+// the named tasks are Satisfied. The names must match task labels.
+//
+// For example:
+//
+//	func (cd *CoreData) ReadConfig(t *initq.Task) initq.ReqResult {
+//		if cd.cmdline == nil {
+//			return t.WaitingOn("cmdline")
+//		}
+//		...
+//	}
+func (t *Task) WaitingOn(names ...string) ReqResult {
+
+	for _, n := range names {
+		if !slices.Contains(t.waits, n) {
+			t.waits = append(t.waits, n)
+		}
+	}
+
+	return TryAgain
+}
+
+/* ======================================================================== */
+
+// TryAgainBecause records the reason the task could not yet be Satisfied,
+// and returns TryAgain. The reason is reported in the diagnostics of an
+// unresolved Q. (Unlike WaitingOn, this does not delay the next call.)
+func (t *Task) TryAgainBecause(reason string) ReqResult {
+
+	t.reason = reason
+
+	return TryAgain
+}
@@ -0,0 +1,146 @@
+package initq
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+/* ======================================================================== */
+
+func TestTaskWaitingOn(t *testing.T) {
+
+	var rq *InitQ
+	var runs map[string]int
+
+	// sense returns a task function that waits on the named task (until it
+	// is Satisfied).
+	sense := func(name string, on string) TaskFunc {
+		return func(t *Task) ReqResult {
+			runs[name]++
+			if t.Name() != name {
+				return Stop
+			}
+			if rq.States()[on] != Satisfied {
+				return t.WaitingOn(on)
+			}
+			return Satisfied
+		}
+	}
+
+	// ----------
+	// A worst case (backwards) ordering. Waiting tasks are only called again
+	// once what they wait on is Satisfied.
+
+	rq = NewInitQ()
+	runs = make(map[string]int)
+
+	rq.AddTask("server", sense("server", "dbconn"))
+	rq.AddTask("dbconn", sense("dbconn", "config"))
+	rq.AddTask("config", sense("config", "cmdline"))
+	rq.Add("cmdline", func() ReqResult { runs["cmdline"]++; return Satisfied })
+
+	if err := rq.Process(); err != nil {
+		t.Errorf("Q did not finish - %s", err.Error())
+	}
+
+	// Without the waits, server would be called 4 times.
+	for _, name := range []string{"server", "dbconn", "config"} {
+		if runs[name] != 2 {
+			t.Errorf("Expected %s to be called twice; got %d", name, runs[name])
+		}
+	}
+
+	if order := SatisfiedOrder(rq.Trace()); !slices.Equal(order, []string{"cmdline", "config", "dbconn", "server"}) {
+		t.Errorf("Unexpected satisfied order %v", order)
+	}
+
+	// ----------
+	// Waits (and reasons) are in the status and the diagnostics.
+
+	rq = NewInitQ()
+	runs = make(map[string]int)
+
+	rq.AddTask("poll", func(t *Task) ReqResult { return t.TryAgainBecause("sidecar not listening") })
+	rq.AddTask("client", sense("client", "poll"))
+
+	err := rq.TryProcess()
+
+	qur, ok := err.(*QUnresolvable)
+	if !ok {
+		t.Fatalf("Expected a *QUnresolvable error; got %v", err)
+	}
+
+	if roots := qur.RootCauses(); !slices.Equal(roots, []string{"poll"}) {
+		t.Errorf("Unexpected root causes %v", roots)
+	}
+
+	for _, d := range qur.Diagnostics() {
+		switch d.Name {
+		case "poll":
+			if d.Reason != "sidecar not listening" {
+				t.Errorf("Unexpected poll diagnostic %+v", d)
+			}
+		case "client":
+			if !slices.Equal(d.WaitingOn, []string{"poll"}) || d.Invocations != 1 || d.RootCause {
+				t.Errorf("Unexpected client diagnostic %+v", d)
+			}
+		}
+	}
+
+	if st := rq.Status(); !slices.Equal(st[1].WaitingOn, []string{"poll"}) || st[0].Reason == "" {
+		t.Errorf("Unexpected status %+v", st)
+	}
+
+	// ----------
+	// Tasks that wait on each other are both root causes.
+
+	rq = NewInitQ()
+	runs = make(map[string]int)
+
+	rq.AddTask("black", sense("black", "white"))
+	rq.AddTask("white", sense("white", "black"))
+
+	qur, _ = rq.TryProcess().(*QUnresolvable)
+	if qur == nil || !slices.Equal(qur.RootCauses(), []string{"black", "white"}) {
+		t.Errorf("Expected a cycle of root causes; got %v", qur)
+	}
+
+	if runs["black"] != 1 {
+		t.Errorf("Expected black to be called once; got %d", runs["black"])
+	}
+
+	// ----------
+	// Waits are validated against the task labels.
+
+	BehaveUnresolvIsErr = true
+
+	rq = NewInitQ()
+
+	rq.AddTask("one", func(t *Task) ReqResult { return t.WaitingOn("typo") })
+
+	if err := rq.Process(); err == nil {
+		t.Errorf("A Q with a dangling wait managed to finish.")
+	} else if !strings.Contains(err.Error(), "typo") {
+		t.Errorf("Expected a specific error - got %s", err.Error())
+	}
+
+	rq = NewInitQ()
+
+	rq.AddTask("one", func(t *Task) ReqResult { return t.WaitingOn("one") })
+
+	if err := rq.Process(); err == nil {
+		t.Errorf("A Q with a self-referencing wait managed to finish.")
+	}
+
+	rq = NewInitQ()
+
+	rq.AddTask("one", nil)
+
+	if err := rq.Process(); err == nil {
+		t.Errorf("A Q with a nil task function managed to finish.")
+	}
+
+	BehaveUnresolvIsErr = false
+
+}
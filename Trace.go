@@ -0,0 +1,90 @@
+package initq
+
+import (
+	"slices"
+	"time"
+)
+
+/* ------------------------------------------------------------------------ */
+
+// Attempt is a record of a single task function invocation. Tasks that are
+// skipped (already Satisfied, or blocked on explicit dependencies) are not
+// invoked, and do not have an Attempt record.
+type Attempt struct {
+	// Task is the label of the task that was invoked.
+	Task string
+
+	// Pass is the (1-based) pass of the Q in which the task was invoked.
+	Pass int
+
+	// Result is the value returned by the task function.
+	Result ReqResult
+
+	// Start and End are when the task function was called and returned.
+	Start time.Time
+	End   time.Time
+}
+
+/* ======================================================================== */
+
+// Trace returns the task function invocations (in order) from the last
+// Process or TryProcess call.
+func (rq *InitQ) Trace() (trace []Attempt) {
+
+	if rq == nil {
+		return nil
+	}
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	return slices.Clone(rq.trace)
+}
+
+/* ======================================================================== */
+
+// Passes returns the number of passes of the Q in the last Process or
+// TryProcess call.
+func (rq *InitQ) Passes() int {
+
+	if rq == nil {
+		return 0
+	}
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	return rq.passes
+}
+
+/* ======================================================================== */
+
+// Invocations returns the number of task function calls in the last Process
+// or TryProcess call.
+func (rq *InitQ) Invocations() int {
+
+	if rq == nil {
+		return 0
+	}
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	return len(rq.trace)
+}
+
+/* ======================================================================== */
+
+// SatisfiedOrder returns the task labels in the order in which they were
+// Satisfied in the trace.
+func SatisfiedOrder(trace []Attempt) (order []string) {
+
+	order = make([]string, 0)
+	for _, a := range trace {
+		if a.Result == Satisfied {
+			order = append(order, a.Task)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,314 @@
+// Package initqtest is a deterministic test harness for applications that
+// use an initq.InitQ.
+//
+// A Q is defined by a Factory - a function that adds all of the tasks to a
+// Q. The factory is called for every run, so it must create fresh state
+// (such as a new core data structure) each time it is called. This allows
+// the same definition to be run many times, with tasks injected with
+// alternate results, or in a shuffled insertion order.
+//
+// For example:
+//
+//	h := initqtest.New(func(add initqtest.AddFunc) {
+//		cd := new(CoreData)
+//		add("cmdline", cd.ParseCommandLine)
+//		add("config", cd.ReadConfig)
+//		add("db", cd.ConnectDB)
+//	})
+//
+//	r := h.Run(t)
+//	r.AssertCompleted(t)
+//	r.AssertSatisfiedBefore(t, "config", "db")
+//	r.AssertWithinPasses(t, 2)
+//
+//	h.AssertOrderIndependent(t, 50)
+package initqtest
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/wfavorite/initq"
+)
+
+/* ------------------------------------------------------------------------ */
+
+// AddFunc matches the InitQ Add method. It is passed to a Factory.
+type AddFunc func(name string, f initq.QFunc, deps ...string)
+
+/* ------------------------------------------------------------------------ */
+
+// Factory defines a Q by calling add for each task. It is called once per
+// run and must not share task state between calls.
+type Factory func(add AddFunc)
+
+/* ------------------------------------------------------------------------ */
+
+// task is a single (captured) Add call from a Factory.
+type task struct {
+	name string
+	f    initq.QFunc
+	deps []string
+}
+
+/* ------------------------------------------------------------------------ */
+
+// Harness runs a Q definition (Factory) with optional injected results.
+type Harness struct {
+	// factory creates the tasks for each run.
+	factory Factory
+
+	// inject are the alternate task functions, by task name.
+	inject map[string]initq.QFunc
+}
+
+/* ------------------------------------------------------------------------ */
+
+// Result is the outcome of a single harness run.
+type Result struct {
+	// Order is the insertion order of the tasks for this run.
+	Order []string
+
+	// Trace is each task invocation, in order.
+	Trace []initq.Attempt
+
+	// Passes is the number of passes of the Q.
+	Passes int
+
+	// States is the final state of each task, by task label.
+	States map[string]initq.ReqResult
+
+	// Err is the error returned from TryProcess.
+	Err error
+
+	// Panic is the recovered value if a task panicked (otherwise nil).
+	Panic any
+
+	// Seed is the seed used to shuffle the insertion order. It is only
+	// meaningful for shuffled runs.
+	Seed uint64
+}
+
+/* ======================================================================== */
+
+// New creates a harness from a Q definition.
+func New(f Factory) (h *Harness) {
+
+	h = new(Harness)
+	h.factory = f
+	h.inject = make(map[string]initq.QFunc)
+
+	return
+}
+
+/* ======================================================================== */
+
+// Inject replaces the named task with one that always returns r. It is
+// typically used to inject a Stop or a TryAgain (that never completes).
+func (h *Harness) Inject(name string, r initq.ReqResult) {
+	h.inject[name] = func() initq.ReqResult { return r }
+}
+
+/* ======================================================================== */
+
+// InjectPanic replaces the named task with one that panics with v.
+func (h *Harness) InjectPanic(name string, v any) {
+	h.inject[name] = func() initq.ReqResult { panic(v) }
+}
+
+/* ======================================================================== */
+
+// tasks calls the factory and returns the (injected) task list in the order
+// that the factory added them.
+func (h *Harness) tasks() (tl []task) {
+
+	h.factory(func(name string, f initq.QFunc, deps ...string) {
+		if alt, ok := h.inject[name]; ok {
+			f = alt
+		}
+		tl = append(tl, task{name: name, f: f, deps: deps})
+	})
+
+	return
+}
+
+/* ======================================================================== */
+
+// Run runs the Q in the order that the factory added the tasks.
+func (h *Harness) Run(t testing.TB) *Result {
+	t.Helper()
+	return run(h.tasks(), 0)
+}
+
+/* ======================================================================== */
+
+// RunShuffled runs the Q with the insertion order shuffled by the seed. The
+// same seed always produces the same order.
+func (h *Harness) RunShuffled(t testing.TB, seed uint64) *Result {
+	t.Helper()
+
+	tl := h.tasks()
+	rnd := rand.New(rand.NewPCG(seed, seed))
+	rnd.Shuffle(len(tl), func(i, j int) { tl[i], tl[j] = tl[j], tl[i] })
+
+	return run(tl, seed)
+}
+
+/* ======================================================================== */
+
+// AssertOrderIndependent runs the Q (runs) times, each with a shuffled
+// insertion order, and fails the test for any run that does not complete.
+// The seed of a failed run is reported so that it may be reproduced with
+// RunShuffled.
+func (h *Harness) AssertOrderIndependent(t testing.TB, runs int) {
+	t.Helper()
+
+	for seed := range uint64(runs) {
+		r := h.RunShuffled(t, seed)
+		if !r.completed() {
+			t.Errorf("Q did not complete with insertion order %v (seed %d) - %s", r.Order, seed, r.failure())
+		}
+	}
+}
+
+/* ======================================================================== */
+
+// Process runs an application-built Q (with TryProcess) and returns the
+// result. It is an alternative to a Harness when the Q is already built.
+func Process(t testing.TB, rq *initq.InitQ) *Result {
+	t.Helper()
+	return process(rq, nil, 0)
+}
+
+/* ======================================================================== */
+
+// run builds a Q from the task list and processes it.
+func run(tl []task, seed uint64) *Result {
+
+	rq := initq.NewInitQ()
+	order := make([]string, 0, len(tl))
+
+	for _, tk := range tl {
+		rq.Add(tk.name, tk.f, tk.deps...)
+		order = append(order, tk.name)
+	}
+
+	return process(rq, order, seed)
+}
+
+/* ======================================================================== */
+
+// process runs the Q and captures the result (to include any panic).
+func process(rq *initq.InitQ, order []string, seed uint64) (r *Result) {
+
+	r = new(Result)
+	r.Order = order
+	r.Seed = seed
+
+	func() {
+		defer func() {
+			r.Panic = recover()
+		}()
+		r.Err = rq.TryProcess()
+	}()
+
+	r.Trace = rq.Trace()
+	r.Passes = rq.Passes()
+	r.States = rq.States()
+
+	return
+}
+
+/* ======================================================================== */
+
+// completed reports if the run finished with all tasks Satisfied.
+func (r *Result) completed() bool {
+	return r.Err == nil && r.Panic == nil
+}
+
+/* ======================================================================== */
+
+// failure describes why a run did not complete.
+func (r *Result) failure() string {
+
+	if r.Panic != nil {
+		return fmt.Sprintf("panic: %v", r.Panic)
+	}
+
+	if r.Err != nil {
+		return r.Err.Error()
+	}
+
+	return "completed"
+}
+
+/* ======================================================================== */
+
+// AssertCompleted fails the test if the Q did not complete.
+func (r *Result) AssertCompleted(t testing.TB) {
+	t.Helper()
+
+	if !r.completed() {
+		t.Errorf("Q did not complete - %s", r.failure())
+	}
+}
+
+/* ======================================================================== */
+
+// AssertStopped fails the test if the Q was not stopped (by a task that
+// returned Stop). The stop may be joined with other errors (such as those of
+// Task.Fail or a compensation).
+func (r *Result) AssertStopped(t testing.TB) {
+	t.Helper()
+
+	if !errors.Is(r.Err, initq.ErrQStopped) {
+		t.Errorf("Expected the Q to be stopped - %s", r.failure())
+	}
+}
+
+/* ======================================================================== */
+
+// AssertPanicked fails the test if no task panicked.
+func (r *Result) AssertPanicked(t testing.TB) {
+	t.Helper()
+
+	if r.Panic == nil {
+		t.Errorf("Expected a task to panic - %s", r.failure())
+	}
+}
+
+/* ======================================================================== */
+
+// AssertSatisfiedBefore fails the test unless both tasks were Satisfied, and
+// the first was Satisfied before the second.
+func (r *Result) AssertSatisfiedBefore(t testing.TB, first, second string) {
+	t.Helper()
+
+	order := initq.SatisfiedOrder(r.Trace)
+
+	fi := slices.Index(order, first)
+	si := slices.Index(order, second)
+
+	switch {
+	case fi < 0:
+		t.Errorf("Task %s was never Satisfied", first)
+	case si < 0:
+		t.Errorf("Task %s was never Satisfied", second)
+	case fi > si:
+		t.Errorf("Task %s was Satisfied after %s (order: %v)", first, second, order)
+	}
+}
+
+/* ======================================================================== */
+
+// AssertWithinPasses fails the test if the Q required more than n passes.
+func (r *Result) AssertWithinPasses(t testing.TB, n int) {
+	t.Helper()
+
+	if r.Passes > n {
+		t.Errorf("Q required %d passes; expected no more than %d", r.Passes, n)
+	}
+}
@@ -0,0 +1,163 @@
+package initqtest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/wfavorite/initq"
+)
+
+/* ======================================================================== */
+
+// coredata is a (minimal) application core. See the initq tests for the
+// long form of this.
+type coredata struct {
+	Cmdl bool
+	Conf bool
+	Data bool
+}
+
+func (cd *coredata) ParseCommandLine() initq.ReqResult {
+	cd.Cmdl = true
+	return initq.Satisfied
+}
+
+func (cd *coredata) ReadConfigFile() initq.ReqResult {
+	if !cd.Cmdl {
+		return initq.TryAgain
+	}
+	cd.Conf = true
+	return initq.Satisfied
+}
+
+func (cd *coredata) SetupDBConnection() initq.ReqResult {
+	if !cd.Conf {
+		return initq.TryAgain
+	}
+	cd.Data = true
+	return initq.Satisfied
+}
+
+// define is the Factory used by the tests.
+func define(add AddFunc) {
+	cd := new(coredata)
+	add("db", cd.SetupDBConnection)
+	add("config", cd.ReadConfigFile)
+	add("cmdline", cd.ParseCommandLine)
+}
+
+// recorder is a testing.TB that counts (rather than reports) failures.
+type recorder struct {
+	testing.TB
+	failures int
+	messages []string
+}
+
+func (rec *recorder) Errorf(format string, args ...any) {
+	rec.failures++
+	rec.messages = append(rec.messages, fmt.Sprintf(format, args...))
+}
+
+/* ======================================================================== */
+
+func TestHarness(t *testing.T) {
+
+	var h *Harness
+	var r *Result
+
+	// ----------
+	// The standard run.
+
+	h = New(define)
+
+	r = h.Run(t)
+	r.AssertCompleted(t)
+	r.AssertSatisfiedBefore(t, "cmdline", "config")
+	r.AssertSatisfiedBefore(t, "config", "db")
+	r.AssertWithinPasses(t, 3)
+
+	// ----------
+	// Order independence.
+
+	h.AssertOrderIndependent(t, 25)
+
+	// The same seed produces the same order.
+	r1 := h.RunShuffled(t, 7)
+	r2 := h.RunShuffled(t, 7)
+	for i := range r1.Order {
+		if r1.Order[i] != r2.Order[i] {
+			t.Errorf("Shuffled order is not deterministic; %v vs %v", r1.Order, r2.Order)
+			break
+		}
+	}
+
+	// ----------
+	// Injected results.
+
+	h = New(define)
+	h.Inject("config", initq.Stop)
+
+	r = h.Run(t)
+	r.AssertStopped(t)
+
+	h = New(define)
+	h.Inject("config", initq.TryAgain)
+
+	r = h.Run(t)
+	if _, ok := r.Err.(*initq.QUnresolvable); !ok {
+		t.Errorf("Expected a *QUnresolvable error; got %v", r.Err)
+	}
+
+	h = New(define)
+	h.InjectPanic("config", "boom")
+
+	r = h.Run(t)
+	r.AssertPanicked(t)
+
+	// ----------
+	// The assertions themselves (on a recorder) must fail when expected.
+
+	h = New(define)
+	h.Inject("config", initq.Stop)
+
+	rec := &recorder{TB: t}
+	r = h.Run(rec)
+	r.AssertCompleted(rec)
+	r.AssertSatisfiedBefore(rec, "config", "db")
+	if rec.failures != 2 {
+		t.Errorf("Expected the assertions to fail on a stopped Q")
+	}
+
+	// ----------
+	// An application-built Q.
+
+	rq := initq.NewInitQ()
+	define(rq.Add)
+
+	r = Process(t, rq)
+	r.AssertCompleted(t)
+
+	// A stop that carries an error (from Task.Fail) is still a stop.
+	rq = initq.NewInitQ()
+	rq.AddTask("db", func(task *initq.Task) initq.ReqResult {
+		return task.Fail(errors.New("no route to host"))
+	})
+
+	r = Process(t, rq)
+	r.AssertStopped(t)
+
+	// As is one joined with the error of a compensation.
+	rq = initq.NewInitQ()
+	rq.Add("config", func() initq.ReqResult { return initq.Satisfied })
+	rq.Add("db", func() initq.ReqResult { return initq.Stop }, "config")
+	rq.SetCompensation("config", func() error { return errors.New("unable to undo") })
+
+	r = Process(t, rq)
+	r.AssertStopped(t)
+
+	if r.Err == initq.ErrQStopped {
+		t.Errorf("Expected the stop to be joined with the compensation error")
+	}
+
+}
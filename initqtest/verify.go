@@ -0,0 +1,197 @@
+package initqtest
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/wfavorite/initq"
+)
+
+/* ------------------------------------------------------------------------ */
+
+// Mismatch is a single run (insertion order) that did not match the baseline
+// (factory order) run.
+type Mismatch struct {
+	// Result is the run that failed verification.
+	Result *Result
+
+	// Reason describes how it failed.
+	Reason string
+}
+
+/* ------------------------------------------------------------------------ */
+
+// Report is the outcome of a Verify call.
+type Report struct {
+	// Runs is the number of insertion orders that were run.
+	Runs int
+
+	// Exhaustive is true when every permutation of the insertion order was
+	// run. Otherwise Runs random permutations were used.
+	Exhaustive bool
+
+	// Baseline is the run in factory order that all others are compared to.
+	Baseline *Result
+
+	// Mismatches are the runs that did not match the Baseline.
+	Mismatches []Mismatch
+}
+
+/* ======================================================================== */
+
+// Verify re-runs the Q with many permutations of the insertion order. If all
+// permutations number no more than max, then all are run. Otherwise max
+// shuffled (seeded 0 to max-1) orders are run.
+//
+// Each run is compared against the factory-order run. Any run that ends in
+// Stop, QUnresolvable, a panic, or a different set of final task states is
+// reported as a Mismatch.
+func (h *Harness) Verify(t testing.TB, max int) (rpt *Report) {
+	t.Helper()
+
+	rpt = new(Report)
+	rpt.Baseline = h.Run(t)
+
+	n := len(rpt.Baseline.Order)
+	if factorial(n, max) <= max {
+
+		rpt.Exhaustive = true
+		permute(n, func(perm []int) {
+			tl := h.tasks()
+			ordered := make([]task, len(tl))
+			for i, p := range perm {
+				ordered[i] = tl[p]
+			}
+			rpt.check(run(ordered, 0))
+		})
+
+		return
+	}
+
+	for seed := range uint64(max) {
+		rpt.check(h.RunShuffled(t, seed))
+	}
+
+	return
+}
+
+/* ======================================================================== */
+
+// AssertVerified runs Verify and fails the test for every Mismatch. The seed
+// (for RunShuffled) is only reported for shuffled runs. An exhaustive run is
+// identified by its insertion order alone.
+func (h *Harness) AssertVerified(t testing.TB, max int) {
+	t.Helper()
+
+	rpt := h.Verify(t, max)
+	for _, m := range rpt.Mismatches {
+		if rpt.Exhaustive {
+			t.Errorf("Insertion order %v: %s", m.Result.Order, m.Reason)
+		} else {
+			t.Errorf("Insertion order %v (seed %d): %s", m.Result.Order, m.Result.Seed, m.Reason)
+		}
+	}
+}
+
+/* ======================================================================== */
+
+// Fuzz is a native Go fuzz target helper. The fuzzed value is the seed used
+// to shuffle the insertion order. Each shuffled run is compared against the
+// factory-order run (as Verify does).
+//
+//	func FuzzStartup(f *testing.F) {
+//		initqtest.Fuzz(f, initqtest.New(define))
+//	}
+func Fuzz(f *testing.F, h *Harness) {
+	f.Helper()
+
+	for seed := range uint64(8) {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, seed uint64) {
+
+		rpt := new(Report)
+		rpt.Baseline = h.Run(t)
+		rpt.check(h.RunShuffled(t, seed))
+
+		for _, m := range rpt.Mismatches {
+			t.Errorf("Insertion order %v (seed %d): %s", m.Result.Order, seed, m.Reason)
+		}
+	})
+}
+
+/* ======================================================================== */
+
+// check compares a run with the baseline and records any mismatch.
+func (rpt *Report) check(r *Result) {
+
+	rpt.Runs++
+
+	var reason string
+
+	switch {
+	case r.Panic != nil:
+		reason = fmt.Sprintf("task panicked: %v", r.Panic)
+	case errors.Is(r.Err, initq.ErrQStopped):
+		reason = "the Q was stopped"
+	case r.Err != nil:
+		reason = r.Err.Error()
+	case !maps.Equal(r.States, rpt.Baseline.States):
+		reason = fmt.Sprintf("final states %v differ from %v", r.States, rpt.Baseline.States)
+	default:
+		return
+	}
+
+	rpt.Mismatches = append(rpt.Mismatches, Mismatch{Result: r, Reason: reason})
+}
+
+/* ======================================================================== */
+
+// factorial returns n! - or limit+1 once the value exceeds limit.
+func factorial(n int, limit int) (f int) {
+
+	f = 1
+	for i := 2; i <= n; i++ {
+		f *= i
+		if f > limit {
+			return limit + 1
+		}
+	}
+
+	return
+}
+
+/* ======================================================================== */
+
+// permute calls fn with every permutation of the indexes 0 to n-1. It is an
+// implementation of Heap's algorithm (non-recursive).
+func permute(n int, fn func(perm []int)) {
+
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+
+	fn(slices.Clone(perm))
+
+	c := make([]int, n)
+	for i := 0; i < n; {
+		if c[i] < i {
+			if i%2 == 0 {
+				perm[0], perm[i] = perm[i], perm[0]
+			} else {
+				perm[c[i]], perm[i] = perm[i], perm[c[i]]
+			}
+			fn(slices.Clone(perm))
+			c[i]++
+			i = 0
+		} else {
+			c[i] = 0
+			i++
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package initqtest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/wfavorite/initq"
+)
+
+/* ======================================================================== */
+
+// fragile is a Factory for a Q that only works in one order. The report
+// task depends on state, but does not wait for it.
+func fragile(add AddFunc) {
+	cd := new(coredata)
+	add("cmdline", cd.ParseCommandLine)
+	add("report", func() initq.ReqResult {
+		if !cd.Cmdl {
+			return initq.Stop
+		}
+		return initq.Satisfied
+	})
+}
+
+/* ======================================================================== */
+
+func TestVerify(t *testing.T) {
+
+	var rpt *Report
+
+	// ----------
+	// A well behaved Q - every permutation (3! = 6).
+
+	rpt = New(define).Verify(t, 100)
+
+	if !rpt.Exhaustive || rpt.Runs != 6 {
+		t.Errorf("Expected 6 exhaustive runs; got %d (exhaustive: %t)", rpt.Runs, rpt.Exhaustive)
+	}
+
+	if len(rpt.Mismatches) != 0 {
+		t.Errorf("Unexpected mismatches: %v", rpt.Mismatches)
+	}
+
+	New(define).AssertVerified(t, 100)
+
+	// ----------
+	// Too many permutations - sampled instead.
+
+	rpt = New(define).Verify(t, 4)
+
+	if rpt.Exhaustive || rpt.Runs != 4 {
+		t.Errorf("Expected 4 sampled runs; got %d (exhaustive: %t)", rpt.Runs, rpt.Exhaustive)
+	}
+
+	// ----------
+	// An order-dependent Q.
+
+	rpt = New(fragile).Verify(t, 100)
+
+	if len(rpt.Mismatches) != 1 {
+		t.Fatalf("Expected 1 mismatch; got %d", len(rpt.Mismatches))
+	}
+
+	if rpt.Mismatches[0].Result.Order[0] != "report" {
+		t.Errorf("Unexpected mismatch order %v", rpt.Mismatches[0].Result.Order)
+	}
+
+	// An exhaustive run has no seed (to report).
+	rec := &recorder{TB: t}
+	New(fragile).AssertVerified(rec, 100)
+
+	if len(rec.messages) != 1 || strings.Contains(rec.messages[0], "seed") {
+		t.Errorf("Unexpected mismatch messages %q", rec.messages)
+	}
+
+	// A stop (joined with other errors) is reported as a stop.
+	rpt = new(Report)
+	rpt.check(&Result{Err: errors.Join(initq.ErrQStopped, errors.New("unable to undo"))})
+
+	if len(rpt.Mismatches) != 1 || rpt.Mismatches[0].Reason != "the Q was stopped" {
+		t.Errorf("Unexpected mismatches %+v", rpt.Mismatches)
+	}
+
+	// The factory is called once per run (and no more).
+	calls := 0
+	New(func(add AddFunc) {
+		calls++
+		define(add)
+	}).Verify(t, 100)
+
+	if calls != 7 {
+		t.Errorf("Expected 7 factory calls (baseline and 6 orders); got %d", calls)
+	}
+
+	// ----------
+	// Permutations are complete and unique.
+
+	seen := make(map[[4]int]bool)
+	permute(4, func(perm []int) {
+		seen[[4]int(perm)] = true
+	})
+
+	if len(seen) != 24 {
+		t.Errorf("Expected 24 unique permutations; got %d", len(seen))
+	}
+
+}
+
+/* ======================================================================== */
+
+func FuzzOrder(f *testing.F) {
+	Fuzz(f, New(define))
+}
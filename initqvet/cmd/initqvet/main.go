@@ -0,0 +1,14 @@
+// Command initqvet runs the initqvet analyzer. It speaks the go vet tool
+// protocol, and is intended to be used as:
+//
+//	go vet -vettool=$(which initqvet) ./...
+package main
+
+import (
+	"github.com/wfavorite/initq/initqvet"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(initqvet.Analyzer)
+}
@@ -0,0 +1,446 @@
+// Package initqvet implements a static analyzer (go/analysis) that finds
+// common misuse of the initq module at compile time.
+//
+// The initq module asserts (log.Fatal) on misuse when Process is called. The
+// design intent is that these problems are caught in test. Many of them are
+// visible in the source, so this analyzer surfaces them even earlier:
+//
+//   - Empty task labels and nil task functions passed to Add (or AddTask).
+//   - Task labels used more than once within the same function.
+//   - Dependencies that reference the task itself.
+//   - Dependencies that do not match any label added (in the same function)
+//     to the same InitQ. Near misses (case differences) are called out.
+//   - Task functions that have no path that returns initq.Satisfied.
+//
+// Label and dependency checks only consider constant strings. Queues built
+// from computed labels (or split across multiple functions) are not flagged
+// for dangling dependencies. A queue is 'open' (not every label can be seen)
+// when any label added to it is not constant, or when it is passed to
+// another call (or assigned elsewhere).
+//
+// The analyzer is intended to be run as a vet tool:
+//
+//	go install github.com/wfavorite/initq/initqvet/cmd/initqvet@latest
+//	go vet -vettool=$(which initqvet) ./...
+package initqvet
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+/* ------------------------------------------------------------------------ */
+
+// initqPath is the import path of the module being checked.
+const initqPath = "github.com/wfavorite/initq"
+
+/* ------------------------------------------------------------------------ */
+
+// Analyzer is the initq misuse analyzer.
+var Analyzer = &analysis.Analyzer{
+	Name:     "initqvet",
+	Doc:      "check for misuse of initq.InitQ Add calls and task functions",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+/* ------------------------------------------------------------------------ */
+
+// queueKey identifies a single InitQ within a function. The object is the
+// receiver variable (nil when the receiver is not a simple identifier), and
+// gen is incremented each time that variable is assigned. This keeps a
+// variable that is reused for several queues from looking like one queue.
+type queueKey struct {
+	obj types.Object
+	gen int
+}
+
+/* ------------------------------------------------------------------------ */
+
+// addCall is a single (recognized) InitQ.Add (or AddTask) call.
+type addCall struct {
+	// call is the call expression - used for positions.
+	call *ast.CallExpr
+
+	// queue identifies the InitQ the call was made on.
+	queue queueKey
+
+	// label is the constant label value. It is only valid if hasLabel.
+	label    string
+	hasLabel bool
+
+	// deps are the dependency arguments (that may or may not be constant).
+	deps []ast.Expr
+}
+
+/* ======================================================================== */
+
+// run is the analysis.Analyzer Run function.
+func run(pass *analysis.Pass) (any, error) {
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	// Method / function declarations are indexed so that task function
+	// references (as opposed to literals) can be inspected.
+	decls := make(map[types.Object]*ast.FuncDecl)
+	for _, f := range pass.Files {
+		for _, d := range f.Decls {
+			if fd, ok := d.(*ast.FuncDecl); ok {
+				if obj := pass.TypesInfo.Defs[fd.Name]; obj != nil {
+					decls[obj] = fd
+				}
+			}
+		}
+	}
+
+	// Walk each function (declared at the top level) for Add calls. Labels
+	// and dependencies are grouped (and cross-checked) by function.
+	filter := []ast.Node{(*ast.FuncDecl)(nil)}
+	insp.Preorder(filter, func(n ast.Node) {
+
+		fd := n.(*ast.FuncDecl)
+		if fd.Body == nil {
+			return
+		}
+
+		calls := make([]*addCall, 0)
+		gens := make(map[types.Object]int)
+		open := make(map[queueKey]bool)
+
+		// escape marks the (variables of the) expressions as open queues.
+		// Labels may be added to them where they cannot be seen.
+		escape := func(exprs ...ast.Expr) {
+			for _, e := range exprs {
+				if id, ok := ast.Unparen(e).(*ast.Ident); ok {
+					if obj := pass.TypesInfo.Uses[id]; obj != nil {
+						open[queueKey{obj: obj, gen: gens[obj]}] = true
+					}
+				}
+			}
+		}
+
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			switch s := n.(type) {
+			case *ast.AssignStmt:
+				escape(s.Rhs...)
+				for _, lhs := range s.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok {
+						gens[pass.TypesInfo.ObjectOf(id)]++
+					}
+				}
+			case *ast.CompositeLit:
+				for _, elt := range s.Elts {
+					if kv, ok := elt.(*ast.KeyValueExpr); ok {
+						elt = kv.Value
+					}
+					escape(elt)
+				}
+			case *ast.CallExpr:
+				if ac := asAddCall(pass, s); ac != nil {
+					ac.queue.gen = gens[ac.queue.obj]
+					calls = append(calls, ac)
+				} else {
+					escape(s.Args...)
+				}
+			}
+			return true
+		})
+
+		checkCalls(pass, calls, open)
+
+		for _, ac := range calls {
+			checkTaskFunc(pass, decls, ac.call.Args[1])
+		}
+	})
+
+	return nil, nil
+}
+
+/* ======================================================================== */
+
+// asAddCall returns an addCall if the call expression is a call of the Add
+// (or AddTask) method on an initq.InitQ. Otherwise it returns nil. Both take
+// the same label and dependency arguments.
+func asAddCall(pass *analysis.Pass, call *ast.CallExpr) (ac *addCall) {
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || (sel.Sel.Name != "Add" && sel.Sel.Name != "AddTask") {
+		return nil
+	}
+
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || !isInitQMethod(fn) {
+		return nil
+	}
+
+	// A call without (at least) the label and function does not type check.
+	if len(call.Args) < 2 {
+		return nil
+	}
+
+	ac = new(addCall)
+	ac.call = call
+
+	if id, ok := ast.Unparen(sel.X).(*ast.Ident); ok {
+		ac.queue.obj = pass.TypesInfo.Uses[id]
+	}
+
+	ac.label, ac.hasLabel = constString(pass, call.Args[0])
+
+	if call.Ellipsis == token.NoPos {
+		ac.deps = call.Args[2:]
+	}
+
+	return
+}
+
+/* ======================================================================== */
+
+// isInitQMethod reports if the function is a method on the initq.InitQ type.
+func isInitQMethod(fn *types.Func) bool {
+
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return false
+	}
+
+	rt := sig.Recv().Type()
+	if ptr, ok := rt.(*types.Pointer); ok {
+		rt = ptr.Elem()
+	}
+
+	named, ok := rt.(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == initqPath && obj.Name() == "InitQ"
+}
+
+/* ======================================================================== */
+
+// constString returns the value of a constant string expression.
+func constString(pass *analysis.Pass, e ast.Expr) (s string, ok bool) {
+
+	tv, found := pass.TypesInfo.Types[e]
+	if !found || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+
+	return constant.StringVal(tv.Value), true
+}
+
+/* ======================================================================== */
+
+// checkCalls cross-checks the labels and dependencies of all Add (and
+// AddTask) calls made within a single function. Dangling dependencies are
+// not reported for open queues (that are added to when a label is not
+// constant).
+func checkCalls(pass *analysis.Pass, calls []*addCall, open map[queueKey]bool) {
+
+	// Labels (per queue) that have been seen in this function.
+	labels := make(map[queueKey]map[string]bool)
+
+	for _, ac := range calls {
+
+		if ac.hasLabel && ac.label == "" {
+			pass.Reportf(ac.call.Args[0].Pos(), "initq: Add called with an empty name label")
+		}
+
+		if isNil(pass, ac.call.Args[1]) {
+			pass.Reportf(ac.call.Args[1].Pos(), "initq: Add(%s) called with a nil function", ac.label)
+		}
+
+		if !ac.hasLabel {
+			open[ac.queue] = true
+			continue
+		}
+
+		if labels[ac.queue] == nil {
+			labels[ac.queue] = make(map[string]bool)
+		}
+
+		if labels[ac.queue][ac.label] {
+			pass.Reportf(ac.call.Args[0].Pos(), "initq: the %s task label was used more than once", ac.label)
+		}
+		labels[ac.queue][ac.label] = true
+	}
+
+	// Dependencies are checked only once all labels are known. Order of the
+	// Add calls is not important (just like the Q itself).
+	for _, ac := range calls {
+		for _, d := range ac.deps {
+
+			dep, ok := constString(pass, d)
+			if !ok {
+				continue
+			}
+
+			if ac.hasLabel && dep == ac.label {
+				pass.Reportf(d.Pos(), "initq: Add(%s) called with a self-referencing dependency", ac.label)
+				continue
+			}
+
+			// Without any constant labels on this queue, the queue was likely
+			// built elsewhere. An open queue may have labels that are not
+			// seen here.
+			known := labels[ac.queue]
+			if len(known) == 0 || known[dep] || open[ac.queue] {
+				continue
+			}
+
+			if near := nearMiss(known, dep); near != "" {
+				pass.Reportf(d.Pos(), "initq: dependency %s does not match any task label (did you mean %s?)", dep, near)
+			} else {
+				pass.Reportf(d.Pos(), "initq: dependency %s does not match any task label", dep)
+			}
+		}
+	}
+}
+
+/* ======================================================================== */
+
+// nearMiss returns a known label that matches dep without regard to case.
+// Labels are case-sensitive, so this is the most likely typo.
+func nearMiss(known map[string]bool, dep string) string {
+
+	for k := range known {
+		if strings.EqualFold(k, dep) {
+			return k
+		}
+	}
+
+	return ""
+}
+
+/* ======================================================================== */
+
+// isNil reports if the expression is the predeclared nil.
+func isNil(pass *analysis.Pass, e ast.Expr) bool {
+
+	tv, ok := pass.TypesInfo.Types[e]
+	return ok && tv.IsNil()
+}
+
+/* ======================================================================== */
+
+// checkTaskFunc reports task functions that can never return Satisfied. Only
+// function literals and functions / methods declared in the package being
+// analyzed can be checked.
+func checkTaskFunc(pass *analysis.Pass, decls map[types.Object]*ast.FuncDecl, f ast.Expr) {
+
+	var body *ast.BlockStmt
+	var name string
+
+	switch fe := ast.Unparen(f).(type) {
+	case *ast.FuncLit:
+		body = fe.Body
+		name = "task function literal"
+	case *ast.Ident:
+		if fd := decls[pass.TypesInfo.Uses[fe]]; fd != nil {
+			body = fd.Body
+			name = fd.Name.Name
+		}
+	case *ast.SelectorExpr:
+		if fd := decls[pass.TypesInfo.Uses[fe.Sel]]; fd != nil {
+			body = fd.Body
+			name = fd.Name.Name
+		}
+	}
+
+	if body == nil {
+		return
+	}
+
+	if !maySatisfy(pass, body) {
+		pass.Reportf(f.Pos(), "initq: %s has no path that returns Satisfied", name)
+	}
+}
+
+/* ======================================================================== */
+
+// maySatisfy reports if any return statement in the (function) body may
+// return Satisfied. Returns of non-constant expressions are assumed to be
+// capable of it. Nested function literals are not considered.
+func maySatisfy(pass *analysis.Pass, body *ast.BlockStmt) (may bool) {
+
+	ast.Inspect(body, func(n ast.Node) bool {
+
+		if may {
+			return false
+		}
+
+		switch s := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			// A bare return (named result) is not traced.
+			if len(s.Results) != 1 {
+				may = true
+				return false
+			}
+
+			tv, ok := pass.TypesInfo.Types[s.Results[0]]
+			if !ok || tv.Value == nil {
+				may = true
+				return false
+			}
+
+			if isSatisfied(pass, s.Results[0]) {
+				may = true
+			}
+		}
+
+		return true
+	})
+
+	return
+}
+
+/* ======================================================================== */
+
+// isSatisfied reports if the expression is the initq.Satisfied constant (or
+// a constant of the same value and type).
+func isSatisfied(pass *analysis.Pass, e ast.Expr) bool {
+
+	tv := pass.TypesInfo.Types[e]
+
+	pkg := findImport(pass.Pkg, initqPath)
+	if pkg == nil {
+		return false
+	}
+
+	sat, ok := pkg.Scope().Lookup("Satisfied").(*types.Const)
+	if !ok {
+		return false
+	}
+
+	return types.Identical(tv.Type, sat.Type()) && constant.Compare(tv.Value, token.EQL, sat.Val())
+}
+
+/* ======================================================================== */
+
+// findImport returns the (directly or indirectly) imported package matching
+// the path. The package being analyzed may *be* the initq package.
+func findImport(pkg *types.Package, path string) *types.Package {
+
+	if pkg.Path() == path {
+		return pkg
+	}
+
+	for _, imp := range pkg.Imports() {
+		if imp.Path() == path {
+			return imp
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+package initqvet
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+/* ======================================================================== */
+
+func TestAnalyzer(t *testing.T) {
+
+	// The example package has 'want' comments for each expected finding.
+	// It imports a stand-in initq package (also under testdata).
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "example")
+
+}
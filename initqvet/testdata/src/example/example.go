@@ -0,0 +1,111 @@
+package example
+
+import "github.com/wfavorite/initq"
+
+const (
+	labelCmdline = "cmdline"
+	labelConfig  = "config"
+)
+
+type coredata struct {
+	cmdl bool
+}
+
+func (cd *coredata) ParseCommandLine() initq.ReqResult {
+	cd.cmdl = true
+	return initq.Satisfied
+}
+
+func (cd *coredata) ReadConfig() initq.ReqResult {
+	if !cd.cmdl {
+		return initq.TryAgain
+	}
+	return initq.Satisfied
+}
+
+// NeverDone forgot to ever report success.
+func (cd *coredata) NeverDone() initq.ReqResult {
+	if !cd.cmdl {
+		return initq.TryAgain
+	}
+	return initq.Stop
+}
+
+// ReadSecrets takes a Task handle (added with AddTask).
+func (cd *coredata) ReadSecrets(t *initq.Task) initq.ReqResult {
+	if !cd.cmdl {
+		return t.WaitingOn(labelCmdline)
+	}
+	return initq.Satisfied
+}
+
+func fromElsewhere() initq.ReqResult { return result() }
+
+func result() initq.ReqResult { return initq.Satisfied }
+
+func good() {
+	cd := new(coredata)
+	iq := initq.NewInitQ()
+
+	iq.Add(labelConfig, cd.ReadConfig, labelCmdline)
+	iq.Add(labelCmdline, cd.ParseCommandLine)
+	iq.Add("other", fromElsewhere)
+
+	// Labels added with AddTask are known to Add (and the reverse).
+	iq.AddTask("secrets", cd.ReadSecrets, labelCmdline)
+	iq.Add("db", cd.ReadConfig, "secrets")
+
+	// The same variable reused for a new queue.
+	iq = initq.NewInitQ()
+	iq.Add(labelCmdline, cd.ParseCommandLine)
+}
+
+func bad() {
+	cd := new(coredata)
+	iq := initq.NewInitQ()
+
+	iq.Add("", cd.ParseCommandLine) // want `Add called with an empty name label`
+	iq.Add("nilfunc", nil)          // want `Add\(nilfunc\) called with a nil function`
+	iq.Add(labelCmdline, cd.ParseCommandLine)
+	iq.Add(labelCmdline, cd.ParseCommandLine)     // want `the cmdline task label was used more than once`
+	iq.Add(labelConfig, cd.ReadConfig, "CmdLine") // want `dependency CmdLine does not match any task label \(did you mean cmdline\?\)`
+	iq.Add("db", cd.ReadConfig, "database")       // want `dependency database does not match any task label`
+	iq.Add("self", cd.ReadConfig, "self")         // want `Add\(self\) called with a self-referencing dependency`
+	iq.Add("never", cd.NeverDone)                 // want `NeverDone has no path that returns Satisfied`
+	iq.Add("lit", func() initq.ReqResult {        // want `task function literal has no path that returns Satisfied`
+		return initq.TryAgain
+	})
+	iq.AddTask("niltask", nil)                    // want `Add\(niltask\) called with a nil function`
+	iq.AddTask("vault", cd.ReadSecrets, "secret") // want `dependency secret does not match any task label`
+}
+
+// registerDB adds the db task to a queue built elsewhere.
+func registerDB(iq *initq.InitQ, cd *coredata) {
+	iq.Add("db2", cd.ReadConfig)
+}
+
+// helper adds labels to the queue (where they cannot be seen).
+func helper() {
+	cd := new(coredata)
+	iq := initq.NewInitQ()
+
+	iq.Add(labelCmdline, cd.ParseCommandLine)
+	registerDB(iq, cd)
+	iq.Add("server", cd.ReadConfig, "db2")
+}
+
+// computed adds labels from a loop (as well as constant labels).
+func computed(names []string) {
+	cd := new(coredata)
+	iq := initq.NewInitQ()
+
+	for _, n := range names {
+		iq.Add(n, cd.ParseCommandLine)
+	}
+	iq.Add("ready", cd.ReadConfig, "lsn1")
+}
+
+// split adds a computed label. The queue is (presumably) built elsewhere.
+func split(iq *initq.InitQ, cd *coredata, name string) {
+	iq.Add(name, cd.ReadConfig, "somewhere")
+}
@@ -0,0 +1,28 @@
+// Package initq is a minimal stand-in for the real module. Only the parts
+// that the analyzer inspects are present.
+package initq
+
+type ReqResult int
+
+const (
+	UnRun ReqResult = iota
+	Satisfied
+	TryAgain
+	Stop
+)
+
+type QFunc func() ReqResult
+
+type Task struct{}
+
+func (t *Task) WaitingOn(names ...string) ReqResult { return TryAgain }
+
+type TaskFunc func(t *Task) ReqResult
+
+type InitQ struct{}
+
+func NewInitQ() *InitQ { return new(InitQ) }
+
+func (rq *InitQ) Add(name string, f QFunc, deps ...string) {}
+
+func (rq *InitQ) AddTask(name string, f TaskFunc, deps ...string) {}
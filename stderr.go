@@ -7,7 +7,7 @@ import (
 /* ------------------------------------------------------------------------ */
 
 // ErrQUnsolvable is returned when the run Q cannot be satisfied in a number
-// of iterations equal to the count of the Q.
+// of iterations equal to the count of the Q (or as set with SetMaxPasses).
 //
 // Assuming a worst case scenario, where the Q was defined perfectly in the
 // wrong order, it should be solvable in a number of iterations equal to the
@@ -24,3 +24,36 @@ var ErrQUnsolvable = fmt.Errorf("unsolvable run Q")
 // Stop InitQResult. This is the one condition that the Process() method errors
 // on - so it can be checked for, but is not a hard requirement to do so.
 var ErrQStopped = fmt.Errorf("run Q early termination")
+
+/* ------------------------------------------------------------------------ */
+
+// ErrQNoTask is returned when a method is called with a task label that does
+// not match any task in the Q.
+var ErrQNoTask = fmt.Errorf("no such task in run Q")
+
+/* ------------------------------------------------------------------------ */
+
+// ErrQRestartBudget is returned by a Supervisor when a task has failed (and
+// been restarted) more times than the restart budget allows.
+var ErrQRestartBudget = fmt.Errorf("run Q restart budget exceeded")
+
+/* ------------------------------------------------------------------------ */
+
+// ErrQCanceled is returned (wrapped with the cause) when the context passed
+// to a processing method is canceled before the Q is complete.
+var ErrQCanceled = fmt.Errorf("run Q canceled")
+
+/* ------------------------------------------------------------------------ */
+
+// ErrQStalled is returned (in place of ErrQUnsolvable) when processing of the
+// Q was abandoned because passes stopped making progress - rather than
+// because the pass budget was exhausted. A QUnresolvable from a stalled Q
+// matches it (with errors.Is).
+var ErrQStalled = fmt.Errorf("run Q stalled")
+
+/* ------------------------------------------------------------------------ */
+
+// ErrQPanicked is the error (wrapped with the panic value) that Observers
+// are given in the EventProcessEnd of a Q where a task function panicked.
+// It is never returned - the panic is re-raised.
+var ErrQPanicked = fmt.Errorf("run Q task panicked")
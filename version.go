@@ -47,10 +47,63 @@ package initq
 	               - Merge of github supplied action and my needs. (Notes are
 	                 in the go.yml file.)
 	               - Multiple unversioned pushes to resolve go.yml action.
+	0.6.0 26-10-18 - Added the initqvet analyzer (go vet -vettool) as its own
+	                 module so that the core module remains dependency free.
+	               - Process now records a trace of task invocations and the
+	                 pass count (Trace(), Passes()).
+	               - Added Simulation (dry-run) with scripted task behaviours.
+	               - Added the initqtest harness package. Make and the test
+	                 action now include sub-packages.
+	               - Added insertion order verification (Verify) and a fuzz
+	                 target helper to initqtest. Added States().
+	               - Added Reset() and ResetTask(). A task that returned Stop
+	                 is re-attempted when the Q is processed again.
+	               - Tasks may Add (child) tasks while the Q is processing.
+	                 The pass budget and label checks follow the Q as it
+	                 grows.
+	               - Added Supervisor (probes, teardown, one-for-one and
+	                 rest-for-one restarts, restart budget and history).
+	               - The InitQ is now safe to read (Status(), States(), etc)
+	                 while it is processing. Task functions are called without
+	                 the lock held.
+	               - Added NewHandler (readiness / liveness http.Handler).
+	               - Added process Events and Observers. A task panic is
+	                 reported to Observers (ErrQPanicked) and re-raised.
+	               - Added Notifier (systemd sd_notify) as an Observer.
+	               - Added ProcessContext / TryProcessContext (ErrQCanceled)
+	                 and background processing with Start / TryStart.
+	               - Trace attempts now have start / end times. Added
+	                 Analyze (critical path, slack, minimum startup time).
+	               - Added learned ordering (LearnOrder, FileOrderStore) and
+	                 Invocations / InvocationsSaved.
+	               - Added Renderer (plain and spinner progress output).
+	               - Added Recorder (spans, Chrome trace_event output, and a
+	                 SpanExporter adapter for tracing systems).
+	               - Added Metrics (Prometheus text format, no dependencies).
+	               - QUnresolvable now has per-task Diagnostics() and the
+	                 RootCauses() of the unresolved Q.
+	               - Added AddTask and the Task handle. Tasks may say what
+	                 they are waiting on (WaitingOn) or why (TryAgainBecause).
+	               - Added SetScheduler. ScheduleEvents only calls a TryAgain
+	                 task again after progress, and stops when there is none.
+	               - Added SetMaxPasses and SetStallPasses. A stalled Q is
+	                 reported as such (ErrQStalled, QUnresolvable.Stalled()).
+	               - Added SetPriority and PreferBefore / PreferAfter soft
+	                 ordering hints.
+	               - Added parallel processing (SetParallel) with named limits
+	                 (SetLimit, Uses) and per task LimitWaits.
+	               - Added SetCompensation. A stopped, canceled or panicked Q
+	                 undoes Satisfied tasks in reverse order of completion.
+	               - Added SetCollectFailures, TaskError and Task.Fail. All
+	                 failures of a run are joined into one error.
+	               - Added Run.WaitSignals (SIGINT / SIGTERM with a grace
+	                 period, Interrupted) and Task.Context.
+	               - Added checkpoints (Checkpoint, FileCheckpointStore) with
+	                 a per task AlwaysRun opt-out.
 */
 
 // VersionString is the version of the project.
-const VersionString = "0.5.0"
+const VersionString = "0.6.0"
 
 /*
 	ToDos: